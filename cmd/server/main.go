@@ -1,29 +1,126 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/006lp/akashchat-api-go/internal/auth"
 	"github.com/006lp/akashchat-api-go/internal/config"
 	"github.com/006lp/akashchat-api-go/internal/handler"
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/006lp/akashchat-api-go/internal/mcpserver"
+	"github.com/006lp/akashchat-api-go/internal/metrics"
+	"github.com/006lp/akashchat-api-go/internal/rpc"
 	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
+// shutdownTimeout bounds how long in-flight requests may run after a shutdown signal
+const shutdownTimeout = 30 * time.Second
+
 func main() {
+	// Stop serving on SIGINT and SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Load configuration
 	cfg := config.Load()
 
+	// Initialize logging. In MCP stdio mode stdout carries the protocol, so logs
+	// and stdout trace spans go to stderr.
+	logOutput := os.Stdout
+	if cfg.MCPTransport == mcpserver.TransportStdio {
+		logOutput = os.Stderr
+	}
+	logger := logging.New(logOutput, cfg.LogLevel, cfg.LogFormat, cfg.LogRedactPrompts)
+	slog.SetDefault(logger)
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.TraceExporter, cfg.ServiceName, logOutput)
+	if err != nil {
+		slog.Error("Failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
+	// Load model aliases and virtual models
+	modelConfig, err := config.LoadModelConfig(cfg.ModelConfigFile, cfg.ModelAliases)
+	if err != nil {
+		slog.Error("Failed to load model config", "error", err)
+		os.Exit(1)
+	}
+
+	if err := service.ValidateContextStrategy(cfg.ContextStrategy); err != nil {
+		slog.Error("Invalid context strategy", "error", err)
+		os.Exit(1)
+	}
+
+	if err := mcpserver.ValidateTransport(cfg.MCPTransport); err != nil {
+		slog.Error("Invalid MCP transport", "error", err)
+		os.Exit(1)
+	}
+
+	apiKeys, err := auth.ParseKeys(cfg.APIKeys)
+	if err != nil {
+		slog.Error("Invalid API_KEYS", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize services
-	sessionService := service.NewSessionService()
-	akashService := service.NewAkashService()
+	akashClient := akash.New(akash.WithBaseURL(cfg.AkashBaseURL), akash.WithHooks(metrics.AkashHooks()))
+	sessionService := service.NewSessionService(akashClient)
+	catalogService := service.NewCatalogService(akashClient, time.Duration(cfg.ModelRefreshSecs)*time.Second)
+	breaker := service.NewCircuitBreaker(cfg.BreakerThreshold, time.Duration(cfg.BreakerCooldown)*time.Second)
+	akashService := service.NewAkashService(akashClient, catalogService, breaker)
+	imageService := service.NewImageService(akashClient, cfg.ImageCacheSize, cfg.ImageCacheMaxBytes, cfg.PublicBaseURL, cfg.TrustedProxies)
+	webhookService := service.NewWebhookService(cfg.WebhookSecret, cfg.WebhookMaxAttempts, cfg.WebhookAllowedHosts)
+	if cfg.WebhookSecret == "" {
+		slog.Warn("WEBHOOK_SECRET is not set, requests with callback_url will be rejected")
+	}
+	aliasService := service.NewAliasService(modelConfig)
+	contextService := service.NewContextService(catalogService, akashService, cfg.ContextStrategy, cfg.ContextSummaryModel, cfg.ContextReserve)
+	responseStore := service.NewResponseStore(cfg.ResponseStoreSize)
+	routerService := service.NewRouterService(sessionService, catalogService, aliasService, contextService)
+	healthService := service.NewHealthService(akashClient, sessionService, catalogService, breaker, time.Duration(cfg.HealthCacheTTL)*time.Second)
+
+	// Start background model catalog refresh
+	catalogService.Start(ctx)
+
+	// In MCP stdio mode the binary only serves MCP to the client that started it
+	mcpServer := mcpserver.NewServer(routerService, akashService, catalogService, aliasService, imageService)
+	if cfg.MCPTransport == mcpserver.TransportStdio {
+		slog.Info("Starting MCP server on stdio")
+		if err := mcpServer.RunStdio(ctx); err != nil {
+			slog.Error("MCP server stopped", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Initialize handlers
-	chatHandler := handler.NewChatHandler(sessionService, akashService)
-	modelHandler := handler.NewModelHandler()
+	chatHandler := handler.NewChatHandler(routerService, akashService, imageService, webhookService, responseStore)
+	modelHandler := handler.NewModelHandler(catalogService, aliasService)
+	imageHandler := handler.NewImageHandler(routerService, akashService, imageService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	tokenizeHandler := handler.NewTokenizeHandler(catalogService, aliasService)
 
 	// Setup Gin router
-	r := gin.Default()
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(logging.Middleware(logger))
 
 	// Setup CORS
 	r.Use(cors.New(cors.Config{
@@ -34,11 +131,60 @@ func main() {
 		AllowCredentials: true,
 	}))
 
+	// Setup tracing and metrics
+	r.Use(tracing.Middleware())
+	r.Use(metrics.Middleware())
+	r.GET("/metrics", metrics.Handler())
+
+	// API routes require a key when API_KEYS is set
+	requireKey := auth.Middleware(apiKeys)
+	if !apiKeys.Enabled() {
+		slog.Warn("API_KEYS is not set, the API is open to every client that can reach it")
+	}
+
 	// Setup routes
-	v1 := r.Group("/v1")
+	v1 := r.Group("/v1", requireKey)
 	{
 		v1.POST("/chat/completions", chatHandler.ChatCompletions)
+		v1.POST("/completions", chatHandler.Completions)
+		v1.POST("/messages", chatHandler.Messages)
+		v1.POST("/responses", chatHandler.Responses)
+		v1.GET("/responses/:id", chatHandler.GetResponse)
+		v1.DELETE("/responses/:id", chatHandler.DeleteResponse)
 		v1.GET("/models", modelHandler.GetModels)
+		v1.GET("/models/:id", modelHandler.GetModel)
+		v1.POST("/images/generations", imageHandler.CreateImage)
+		v1.GET("/webhooks/deliveries/:id", webhookHandler.GetDelivery)
+		v1.POST("/tokenize", tokenizeHandler.Tokenize)
+	}
+
+	// Browsers cannot send headers on WebSocket handshakes, so the chat socket
+	// also takes the key from a subprotocol or query parameter and checks origins
+	r.GET("/v1/ws/chat", auth.WebSocketMiddleware(apiKeys, cfg.WSAllowedOrigins), chatHandler.WebSocketChat)
+
+	// Generated image files stay public, so that the URLs handed to clients can be
+	// embedded in pages and chat apps. Their names are unguessable job IDs.
+	r.GET("/v1/images/:name", imageHandler.GetImage)
+
+	// Gemini-compatible routes
+	v1beta := r.Group("/v1beta", requireKey)
+	{
+		v1beta.POST("/models/:action", chatHandler.GeminiGenerate)
+	}
+
+	// Ollama-compatible routes
+	ollama := r.Group("/api", requireKey)
+	{
+		ollama.POST("/chat", chatHandler.OllamaChat)
+		ollama.POST("/generate", chatHandler.OllamaGenerate)
+		ollama.GET("/tags", modelHandler.OllamaTags)
+		ollama.POST("/show", modelHandler.OllamaShow)
+		ollama.GET("/version", modelHandler.OllamaVersion)
+	}
+
+	// MCP streamable HTTP endpoint
+	if cfg.MCPTransport == mcpserver.TransportHTTP {
+		r.Any("/mcp", requireKey, gin.WrapH(mcpServer.HTTPHandler()))
 	}
 
 	// Health check endpoint
@@ -49,9 +195,84 @@ func main() {
 		})
 	})
 
-	// Start server
-	log.Printf("Starting server on %s", cfg.ServerAddress)
-	if err := r.Run(cfg.ServerAddress); err != nil {
-		log.Fatal("Failed to start server: ", err)
+	// Liveness and readiness probes
+	r.GET("/livez", healthHandler.Livez)
+	r.GET("/readyz", healthHandler.Readyz)
+
+	// Bind the listeners before serving, so that an address in use stops startup
+	httpListener, err := net.Listen("tcp", cfg.ServerAddress)
+	if err != nil {
+		slog.Error("Failed to listen", "address", cfg.ServerAddress, "error", err)
+		os.Exit(1)
+	}
+	httpServer := &http.Server{Handler: r}
+
+	// The gRPC server is only started when GRPC_ADDRESS is set
+	var grpcServer *grpc.Server
+	var grpcListener net.Listener
+	if cfg.GRPCAddress != "" {
+		grpcListener, err = net.Listen("tcp", cfg.GRPCAddress)
+		if err != nil {
+			slog.Error("Failed to listen for gRPC", "address", cfg.GRPCAddress, "error", err)
+			os.Exit(1)
+		}
+		if cfg.PublicBaseURL == "" {
+			slog.Warn("PUBLIC_BASE_URL is not set, gRPC image URLs will point to Akash instead of the image proxy")
+		}
+		grpcServer = rpc.NewGRPCServer(rpc.NewServer(routerService, akashService, catalogService, aliasService, imageService), logger, apiKeys)
+	}
+
+	serveErr := make(chan error, 2)
+	go func() {
+		slog.Info("Starting server", "address", cfg.ServerAddress)
+		if err := httpServer.Serve(httpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- fmt.Errorf("HTTP server: %w", err)
+		}
+	}()
+	if grpcServer != nil {
+		go func() {
+			slog.Info("Starting gRPC server", "address", cfg.GRPCAddress)
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				serveErr <- fmt.Errorf("gRPC server: %w", err)
+			}
+		}()
+	}
+
+	// Run until a signal arrives or a server fails, then drain both servers
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down")
+	case err := <-serveErr:
+		slog.Error("Server stopped", "error", err)
+		exitCode = 1
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		if grpcServer != nil {
+			grpcServer.GracefulStop()
+		}
+		close(grpcStopped)
+	}()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server shutdown failed", "error", err)
+		exitCode = 1
+	}
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+	}
+
+	if exitCode != 0 {
+		shutdownTracing(context.Background())
+		os.Exit(exitCode)
 	}
 }
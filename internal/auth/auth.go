@@ -0,0 +1,194 @@
+// Package auth restricts the API to clients presenting a configured API key
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// KeyNameKey is the gin context key holding the name of the key a request was
+// authenticated with
+const KeyNameKey = "auth.key_name"
+
+// WebSocketProtocol is the subprotocol of the chat WebSocket. Browsers cannot set
+// headers on WebSocket handshakes, so they offer it together with a bearer.<key>
+// subprotocol carrying the API key, and the server echoes WebSocketProtocol back.
+const WebSocketProtocol = "akashchat"
+
+// webSocketKeyPrefix marks the subprotocol carrying an API key
+const webSocketKeyPrefix = "bearer."
+
+// Keys maps API keys to the names they are logged under
+type Keys struct {
+	keys []apiKey
+}
+
+// apiKey is a single configured key
+type apiKey struct {
+	name string
+	key  []byte
+}
+
+// ParseKeys parses a comma separated list of name:key pairs. A key given
+// without a name is named after its position, such as key-1. An empty spec
+// disables authentication.
+func ParseKeys(spec string) (*Keys, error) {
+	k := &Keys{}
+	for i, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, key, ok := strings.Cut(entry, ":")
+		if !ok {
+			name, key = "key-"+strconv.Itoa(i+1), entry
+		}
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if name == "" || key == "" {
+			return nil, fmt.Errorf("invalid API key entry %d: expected name:key", i+1)
+		}
+		k.keys = append(k.keys, apiKey{name: name, key: []byte(key)})
+	}
+	return k, nil
+}
+
+// Enabled reports whether any keys are configured
+func (k *Keys) Enabled() bool {
+	return k != nil && len(k.keys) > 0
+}
+
+// Lookup returns the name of key, comparing against every configured key in
+// constant time
+func (k *Keys) Lookup(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+
+	name, found := "", false
+	for _, candidate := range k.keys {
+		if subtle.ConstantTimeCompare([]byte(key), candidate.key) == 1 && !found {
+			name, found = candidate.name, true
+		}
+	}
+	return name, found
+}
+
+// FromHeaders returns the API key carried by a request. It accepts a bearer
+// token as well as the x-api-key and x-goog-api-key headers used by Anthropic
+// and Gemini clients. get returns the value of the named header.
+func FromHeaders(get func(name string) string) string {
+	if value := get("Authorization"); len(value) > 7 && strings.EqualFold(value[:7], "bearer ") {
+		return strings.TrimSpace(value[7:])
+	}
+	if value := get("X-Api-Key"); value != "" {
+		return value
+	}
+	return get("X-Goog-Api-Key")
+}
+
+// FromWebSocket returns the API key carried by a WebSocket handshake. Besides the
+// headers accepted by FromHeaders, it accepts a bearer.<key> subprotocol and an
+// api_key query parameter, since browsers cannot set headers on WebSockets.
+func FromWebSocket(r *http.Request) string {
+	if key := FromHeaders(r.Header.Get); key != "" {
+		return key
+	}
+	for _, protocol := range websocket.Subprotocols(r) {
+		if key, ok := strings.CutPrefix(protocol, webSocketKeyPrefix); ok {
+			return key
+		}
+	}
+	return r.URL.Query().Get("api_key")
+}
+
+// Middleware rejects requests without a valid API key and records the name of
+// the key on the request log. It does nothing when no keys are configured.
+func Middleware(keys *Keys) gin.HandlerFunc {
+	return requireKey(keys, func(c *gin.Context) string {
+		return FromHeaders(c.GetHeader)
+	})
+}
+
+// WebSocketMiddleware guards WebSocket handshakes. Cross-origin handshakes are
+// rejected unless their origin is in allowedOrigins, a comma separated list in
+// which "*" allows every origin. Keys are then checked like Middleware, also
+// accepting those carried as described in FromWebSocket.
+func WebSocketMiddleware(keys *Keys, allowedOrigins string) gin.HandlerFunc {
+	origins := make(map[string]bool)
+	for _, origin := range strings.Split(allowedOrigins, ",") {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		if origin != "" {
+			origins[origin] = true
+		}
+	}
+
+	authenticate := requireKey(keys, func(c *gin.Context) string {
+		return FromWebSocket(c.Request)
+	})
+	return func(c *gin.Context) {
+		if !originAllowed(c.Request, origins) {
+			c.AbortWithStatusJSON(http.StatusForbidden, model.APIResponse{
+				Code: 403,
+				Data: model.ErrorData{
+					Message: "Origin not allowed: " + c.GetHeader("Origin"),
+					Type:    "permission_error",
+				},
+			})
+			return
+		}
+		authenticate(c)
+	}
+}
+
+// originAllowed reports whether the Origin of r is allowed. Requests without an
+// Origin come from non-browser clients, and same-origin pages are always allowed.
+func originAllowed(r *http.Request, origins map[string]bool) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origins["*"] || origins[strings.ToLower(origin)] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// requireKey returns a middleware checking the key returned by extract
+func requireKey(keys *Keys, extract func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !keys.Enabled() {
+			c.Next()
+			return
+		}
+
+		name, ok := keys.Lookup(extract(c))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, model.APIResponse{
+				Code: 401,
+				Data: model.ErrorData{
+					Message: "Invalid or missing API key",
+					Type:    "authentication_error",
+				},
+			})
+			return
+		}
+
+		c.Set(KeyNameKey, name)
+		logging.Set(c.Request.Context(), logging.FieldKeyName, name)
+		c.Next()
+	}
+}
+
+// KeyName returns the name of the key the request was authenticated with, or
+// an empty string when authentication is disabled
+func KeyName(c *gin.Context) string {
+	return c.GetString(KeyNameKey)
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("ci:secret-1, secret-2 ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := keys.Lookup("secret-1"); !ok || name != "ci" {
+		t.Errorf("Lookup(secret-1) = %q, %v", name, ok)
+	}
+	if name, ok := keys.Lookup("secret-2"); !ok || name != "key-2" {
+		t.Errorf("Lookup(secret-2) = %q, %v", name, ok)
+	}
+	if _, ok := keys.Lookup("secret"); ok {
+		t.Error("Lookup accepted a prefix of a key")
+	}
+
+	if _, err := ParseKeys("ci:"); err == nil {
+		t.Error("ParseKeys accepted an empty key")
+	}
+	if keys, _ := ParseKeys(""); keys.Enabled() {
+		t.Error("empty spec enabled authentication")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys, _ := ParseKeys("ci:secret")
+
+	var logs bytes.Buffer
+	r := gin.New()
+	r.Use(logging.Middleware(logging.New(&logs, "info", "json", true)))
+	r.GET("/v1/models", Middleware(keys), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		header, value string
+		want          int
+	}{
+		{"", "", http.StatusUnauthorized},
+		{"Authorization", "Bearer wrong", http.StatusUnauthorized},
+		{"Authorization", "Bearer secret", http.StatusOK},
+		{"X-Api-Key", "secret", http.StatusOK},
+		{"X-Goog-Api-Key", "secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		logs.Reset()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.want {
+			t.Errorf("%s %q: status = %d, want %d", tt.header, tt.value, w.Code, tt.want)
+		}
+		if tt.want == http.StatusOK && !strings.Contains(logs.String(), `"key_name":"ci"`) {
+			t.Errorf("%s: request log %s does not name the key", tt.header, logs.String())
+		}
+	}
+}
+
+func TestWebSocketMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys, _ := ParseKeys("ci:secret")
+
+	r := gin.New()
+	r.GET("/v1/ws/chat", WebSocketMiddleware(keys, "https://app.example.com/"), func(c *gin.Context) {
+		c.String(http.StatusOK, KeyName(c))
+	})
+
+	tests := []struct {
+		name    string
+		target  string
+		headers map[string]string
+		want    int
+	}{
+		{"no key", "/v1/ws/chat", nil, http.StatusUnauthorized},
+		{"header", "/v1/ws/chat", map[string]string{"Authorization": "Bearer secret"}, http.StatusOK},
+		{"subprotocol", "/v1/ws/chat", map[string]string{"Sec-WebSocket-Protocol": "akashchat, bearer.secret"}, http.StatusOK},
+		{"wrong subprotocol key", "/v1/ws/chat", map[string]string{"Sec-WebSocket-Protocol": "akashchat, bearer.wrong"}, http.StatusUnauthorized},
+		{"query", "/v1/ws/chat?api_key=secret", nil, http.StatusOK},
+		{"same origin", "/v1/ws/chat?api_key=secret", map[string]string{"Origin": "http://example.com"}, http.StatusOK},
+		{"allowed origin", "/v1/ws/chat?api_key=secret", map[string]string{"Origin": "https://APP.example.com"}, http.StatusOK},
+		{"cross origin", "/v1/ws/chat?api_key=secret", map[string]string{"Origin": "https://evil.example.net"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && w.Body.String() != "ci" {
+				t.Errorf("key name = %q, want ci", w.Body.String())
+			}
+		})
+	}
+
+	// Cross-origin handshakes are refused even when keys are disabled
+	open := gin.New()
+	open.GET("/v1/ws/chat", WebSocketMiddleware(nil, ""), func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/ws/chat", nil)
+	req.Header.Set("Origin", "https://evil.example.net")
+	open.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("cross-origin status without keys = %d, want 403", w.Code)
+	}
+}
+
+func TestKeysOnlyFromHeadersOutsideWebSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys, _ := ParseKeys("ci:secret")
+
+	r := gin.New()
+	r.GET("/v1/models", Middleware(keys), func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/models?api_key=secret", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status with a query key = %d, want 401", w.Code)
+	}
+}
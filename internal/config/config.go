@@ -2,23 +2,76 @@ package config
 
 import (
 	"os"
+	"strconv"
 )
 
 // Config holds the application configuration
 type Config struct {
-	ServerAddress    string
-	AkashBaseURL     string
-	DefaultTimeout   int
-	SessionCacheSize int
+	ServerAddress       string
+	PublicBaseURL       string
+	TrustedProxies      string
+	APIKeys             string
+	GRPCAddress         string
+	AkashBaseURL        string
+	DefaultTimeout      int
+	SessionCacheSize    int
+	ImageCacheSize      int
+	ImageCacheMaxBytes  int
+	WebhookSecret       string
+	WebhookMaxAttempts  int
+	WebhookAllowedHosts string
+	WSAllowedOrigins    string
+	TraceExporter       string
+	ServiceName         string
+	LogLevel            string
+	LogFormat           string
+	LogRedactPrompts    bool
+	HealthCacheTTL      int
+	BreakerThreshold    int
+	BreakerCooldown     int
+	ModelRefreshSecs    int
+	ModelConfigFile     string
+	ModelAliases        string
+	ContextStrategy     string
+	ContextSummaryModel string
+	ContextReserve      int
+	ResponseStoreSize   int
+	MCPTransport        string
 }
 
 // Load loads configuration from environment variables with defaults
 func Load() *Config {
 	cfg := &Config{
-		ServerAddress:    getEnv("SERVER_ADDRESS", "localhost:16571"),
-		AkashBaseURL:     getEnv("AKASH_BASE_URL", "https://chat.akash.network"),
-		DefaultTimeout:   60,
-		SessionCacheSize: 100,
+		ServerAddress:       getEnv("SERVER_ADDRESS", "localhost:16571"),
+		PublicBaseURL:       getEnv("PUBLIC_BASE_URL", ""),
+		TrustedProxies:      getEnv("TRUSTED_PROXIES", ""),
+		APIKeys:             getEnv("API_KEYS", ""),
+		GRPCAddress:         getEnv("GRPC_ADDRESS", ""),
+		AkashBaseURL:        getEnv("AKASH_BASE_URL", "https://chat.akash.network"),
+		DefaultTimeout:      60,
+		SessionCacheSize:    100,
+		ImageCacheSize:      getEnvInt("IMAGE_CACHE_SIZE", 64),
+		ImageCacheMaxBytes:  getEnvInt("IMAGE_CACHE_MAX_BYTES", 256<<20),
+		WebhookSecret:       getEnv("WEBHOOK_SECRET", ""),
+		WebhookMaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		WebhookAllowedHosts: getEnv("WEBHOOK_ALLOWED_HOSTS", ""),
+		WSAllowedOrigins:    getEnv("WS_ALLOWED_ORIGINS", ""),
+		TraceExporter:       getEnv("OTEL_TRACES_EXPORTER", "none"),
+		ServiceName:         getEnv("OTEL_SERVICE_NAME", "akashchat-api-go"),
+		LogLevel:            getEnv("LOG_LEVEL", "info"),
+		LogFormat:           getEnv("LOG_FORMAT", "json"),
+		LogRedactPrompts:    getEnvBool("LOG_REDACT_PROMPTS", true),
+		HealthCacheTTL:      getEnvInt("HEALTH_CACHE_TTL", 15),
+		BreakerThreshold:    getEnvInt("CIRCUIT_BREAKER_THRESHOLD", 5),
+		BreakerCooldown:     getEnvInt("CIRCUIT_BREAKER_COOLDOWN", 30),
+		ModelRefreshSecs:    getEnvInt("MODEL_REFRESH_INTERVAL", 300),
+		ModelConfigFile:     getEnv("MODEL_CONFIG_FILE", ""),
+		ModelAliases:        getEnv("MODEL_ALIASES", ""),
+		ContextStrategy:     getEnv("CONTEXT_STRATEGY", "truncate"),
+		ContextSummaryModel: getEnv("CONTEXT_SUMMARY_MODEL", "Meta-Llama-3-1-8B-Instruct-FP8"),
+		ContextReserve:      getEnvInt("CONTEXT_RESERVE_TOKENS", 1024),
+		ResponseStoreSize:   getEnvInt("RESPONSE_STORE_SIZE", 1000),
+		MCPTransport:        getEnv("MCP_TRANSPORT", "off"),
 	}
 
 	return cfg
@@ -30,4 +83,24 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvBool gets boolean environment variable with default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getEnvInt gets integer environment variable with default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
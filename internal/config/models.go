@@ -0,0 +1,107 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxAliasDepth limits how many aliases may be chained before reaching a real model
+const maxAliasDepth = 8
+
+// VirtualModel bundles a target model with a system prompt and sampling presets
+type VirtualModel struct {
+	ID           string   `json:"id"`
+	Model        string   `json:"model"`
+	Description  string   `json:"description,omitempty"`
+	SystemPrompt string   `json:"system_prompt,omitempty"`
+	Temperature  *float64 `json:"temperature,omitempty"`
+	TopP         *float64 `json:"top_p,omitempty"`
+}
+
+// ModelConfig holds model aliases, virtual models and fallback chains
+type ModelConfig struct {
+	Aliases       map[string]string   `json:"aliases"`
+	VirtualModels []VirtualModel      `json:"virtual_models"`
+	Fallbacks     map[string][]string `json:"fallbacks"`
+}
+
+// LoadModelConfig reads the model config file at path, if any, and merges in
+// aliases given as a comma separated list of name=model pairs
+func LoadModelConfig(path, aliases string) (*ModelConfig, error) {
+	cfg := &ModelConfig{}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read model config: %w", err)
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse model config: %w", err)
+		}
+	}
+
+	if cfg.Aliases == nil {
+		cfg.Aliases = make(map[string]string)
+	}
+
+	for _, pair := range strings.Split(aliases, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, target, ok := strings.Cut(pair, "=")
+		name, target = strings.TrimSpace(name), strings.TrimSpace(target)
+		if !ok || name == "" || target == "" {
+			return nil, fmt.Errorf("invalid model alias %q: expected name=model", pair)
+		}
+		cfg.Aliases[name] = target
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validate rejects incomplete entries, duplicate names and alias cycles
+func (c *ModelConfig) validate() error {
+	targets := make(map[string]string, len(c.Aliases)+len(c.VirtualModels))
+	for name, target := range c.Aliases {
+		targets[name] = target
+	}
+
+	for _, vm := range c.VirtualModels {
+		if vm.ID == "" || vm.Model == "" {
+			return fmt.Errorf("virtual model %q must have an id and a model", vm.ID)
+		}
+		if _, exists := targets[vm.ID]; exists {
+			return fmt.Errorf("model name %q is defined more than once", vm.ID)
+		}
+		targets[vm.ID] = vm.Model
+	}
+
+	for name, chain := range c.Fallbacks {
+		for _, fallback := range chain {
+			if fallback == "" {
+				return fmt.Errorf("fallback chain for %q contains an empty model", name)
+			}
+		}
+	}
+
+	for name := range targets {
+		current := name
+		for depth := 0; ; depth++ {
+			next, ok := targets[current]
+			if !ok {
+				break
+			}
+			if depth >= maxAliasDepth {
+				return fmt.Errorf("model alias %q does not resolve to a model (cycle or chain longer than %d)", name, maxAliasDepth)
+			}
+			current = next
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeModelConfig writes data to a model config file and returns its path
+func writeModelConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "models.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadModelConfig(t *testing.T) {
+	path := writeModelConfig(t, `{
+		"aliases": {"fast": "Meta-Llama-3-3-70B-Instruct"},
+		"virtual_models": [{"id": "coder", "model": "fast", "temperature": 0.2}],
+		"fallbacks": {"fast": ["Qwen3-235B-A22B-Instruct-2507-FP8"]}
+	}`)
+
+	cfg, err := LoadModelConfig(path, " smart = Qwen3-235B-A22B-Instruct-2507-FP8 , fast=DeepSeek-R1")
+	if err != nil {
+		t.Fatalf("LoadModelConfig() error = %v", err)
+	}
+	// Aliases given in the environment are merged over the file
+	if cfg.Aliases["smart"] != "Qwen3-235B-A22B-Instruct-2507-FP8" || cfg.Aliases["fast"] != "DeepSeek-R1" {
+		t.Errorf("aliases = %v", cfg.Aliases)
+	}
+	if len(cfg.VirtualModels) != 1 || *cfg.VirtualModels[0].Temperature != 0.2 {
+		t.Errorf("virtual models = %+v", cfg.VirtualModels)
+	}
+	if len(cfg.Fallbacks["fast"]) != 1 {
+		t.Errorf("fallbacks = %v", cfg.Fallbacks)
+	}
+}
+
+func TestLoadModelConfigRejectsInvalidConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		aliases string
+		wantErr string
+	}{
+		{"alias cycle", `{"aliases": {"a": "b", "b": "a"}}`, "", "does not resolve"},
+		{"self alias", "", "a=a", "does not resolve"},
+		{"cycle through a virtual model", `{"aliases": {"a": "v"}, "virtual_models": [{"id": "v", "model": "a"}]}`, "", "does not resolve"},
+		{"cycle across file and environment", `{"aliases": {"a": "b"}}`, "b=a", "does not resolve"},
+		{"chain too long", `{"aliases": {"a1": "a2", "a2": "a3", "a3": "a4", "a4": "a5", "a5": "a6", "a6": "a7", "a7": "a8", "a8": "a9", "a9": "a10", "a10": "model"}}`, "", "does not resolve"},
+		{"virtual model without a model", `{"virtual_models": [{"id": "v"}]}`, "", "must have an id and a model"},
+		{"duplicate name", `{"aliases": {"v": "model"}, "virtual_models": [{"id": "v", "model": "model"}]}`, "", "defined more than once"},
+		{"empty fallback", `{"fallbacks": {"model": [""]}}`, "", "empty model"},
+		{"malformed alias", "", "fast", "expected name=model"},
+		{"invalid JSON", `{"aliases": [}`, "", "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.file != "" {
+				path = writeModelConfig(t, tt.file)
+			}
+			_, err := LoadModelConfig(path, tt.aliases)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadModelConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
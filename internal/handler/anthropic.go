@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/006lp/akashchat-api-go/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Messages handles the Anthropic-compatible /v1/messages endpoint
+func (h *ChatHandler) Messages(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ChatHandler.Messages")
+	defer span.End()
+
+	var areq model.AnthropicMessagesRequest
+	if err := c.ShouldBindJSON(&areq); err != nil {
+		writeAnthropicError(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	req, err := fromAnthropic(areq)
+	if err != nil {
+		writeAnthropicError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if e := h.routeModel(ctx, c, &req); e != nil {
+		writeAnthropicError(c, e.Status, e.Message)
+		return
+	}
+	defer h.recordModel(ctx, c, &req)
+
+	if req.Model == "AkashGen" {
+		writeAnthropicError(c, http.StatusBadRequest, "Image generation is not supported by the Messages API")
+		return
+	}
+
+	sessionToken, e := h.startRequest(ctx, c, &req)
+	if e != nil {
+		writeAnthropicError(c, e.Status, e.Message)
+		return
+	}
+
+	temperature, topP := service.SamplingParams(req)
+
+	if areq.Stream {
+		h.streamAnthropic(ctx, c, req, sessionToken, temperature, topP)
+		return
+	}
+
+	var generation *service.Generation
+	err = h.routerService.WithFallback(ctx, &req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+		c.Header(ModelHeader, attempt.Model)
+		generation, err = h.akashService.GenerateText(ctx, attempt, sessionToken, temperature, topP)
+		return err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		c.Error(err)
+		writeAnthropicError(c, http.StatusInternalServerError, "Text generation failed: "+err.Error())
+		return
+	}
+
+	stopReason, stopSequence := anthropicStopReason(generation.FinishReason, generation.StopSequence)
+	c.JSON(http.StatusOK, model.AnthropicMessagesResponse{
+		ID:           anthropicMessageID(generation.MessageID),
+		Type:         "message",
+		Role:         "assistant",
+		Model:        req.Model,
+		Content:      []model.AnthropicContentBlock{{Type: "text", Text: generation.Content}},
+		StopReason:   &stopReason,
+		StopSequence: stopSequence,
+		Usage: model.AnthropicUsage{
+			InputTokens:  generation.Usage.PromptTokens,
+			OutputTokens: generation.Usage.CompletionTokens,
+		},
+	})
+}
+
+// streamAnthropic streams a generation as the Anthropic server-sent event sequence
+func (h *ChatHandler) streamAnthropic(ctx context.Context, c *gin.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64) {
+	index := 0
+
+	err := h.routerService.WithFallback(ctx, &req, c.Writer.Written, func(attempt model.ChatCompletionRequest) error {
+		c.Header(ModelHeader, attempt.Model)
+		return h.akashService.StreamTextGeneration(ctx, attempt, sessionToken, temperature, topP, func(event service.StreamEvent) error {
+			switch event.Type {
+			case service.StreamStart:
+				startSSE(c)
+				writeSSE(c, "message_start", model.AnthropicStreamEvent{
+					Type: "message_start",
+					Message: &model.AnthropicMessagesResponse{
+						ID:      anthropicMessageID(event.MessageID),
+						Type:    "message",
+						Role:    "assistant",
+						Model:   attempt.Model,
+						Content: []model.AnthropicContentBlock{},
+						Usage: model.AnthropicUsage{
+							InputTokens: service.CountRequestTokens(h.akashService.Tokenizer(ctx, attempt.Model), attempt),
+						},
+					},
+				})
+				writeSSE(c, "content_block_start", model.AnthropicStreamEvent{
+					Type:         "content_block_start",
+					Index:        &index,
+					ContentBlock: &model.AnthropicContentBlock{Type: "text", Text: ""},
+				})
+				writeSSE(c, "ping", model.AnthropicStreamEvent{Type: "ping"})
+			case service.StreamDelta:
+				writeSSE(c, "content_block_delta", model.AnthropicStreamEvent{
+					Type:  "content_block_delta",
+					Index: &index,
+					Delta: &model.AnthropicDelta{Type: "text_delta", Text: event.Content},
+				})
+			case service.StreamFinish:
+				stopReason, stopSequence := anthropicStopReason(event.FinishReason, event.StopSequence)
+				writeSSE(c, "content_block_stop", model.AnthropicStreamEvent{
+					Type:  "content_block_stop",
+					Index: &index,
+				})
+				writeSSE(c, "message_delta", model.AnthropicStreamEvent{
+					Type:  "message_delta",
+					Delta: &model.AnthropicDelta{StopReason: &stopReason, StopSequence: stopSequence},
+					Usage: &model.AnthropicUsage{
+						InputTokens:  event.Usage.PromptTokens,
+						OutputTokens: event.Usage.CompletionTokens,
+					},
+				})
+				writeSSE(c, "message_stop", model.AnthropicStreamEvent{Type: "message_stop"})
+			}
+			return nil
+		})
+	})
+	if err == nil {
+		return
+	}
+
+	tracing.RecordError(trace.SpanFromContext(ctx), err)
+	c.Error(err)
+	if !c.Writer.Written() {
+		writeAnthropicError(c, http.StatusInternalServerError, "Text generation failed: "+err.Error())
+		return
+	}
+	writeSSE(c, "error", model.AnthropicStreamEvent{
+		Type:  "error",
+		Error: &model.AnthropicError{Type: "api_error", Message: err.Error()},
+	})
+}
+
+// fromAnthropic converts an Anthropic Messages request to a chat completion request.
+// The top-level system prompt becomes a leading system message.
+func fromAnthropic(areq model.AnthropicMessagesRequest) (model.ChatCompletionRequest, error) {
+	req := model.ChatCompletionRequest{
+		Model:       areq.Model,
+		Temperature: areq.Temperature,
+		TopP:        areq.TopP,
+		Stream:      &areq.Stream,
+		Stop:        areq.StopSequences,
+	}
+	if areq.MaxTokens > 0 {
+		req.MaxTokens = &areq.MaxTokens
+	}
+
+	if len(areq.System) > 0 {
+		system, err := anthropicText(areq.System)
+		if err != nil {
+			return req, err
+		}
+		req.Messages = append(req.Messages, model.ChatMessage{Role: "system", Content: system})
+	}
+
+	for _, msg := range areq.Messages {
+		if msg.Role != "user" && msg.Role != "assistant" {
+			return req, fmt.Errorf("messages: unexpected role %q, expected user or assistant", msg.Role)
+		}
+		text, err := anthropicText(msg.Content)
+		if err != nil {
+			return req, err
+		}
+		req.Messages = append(req.Messages, model.ChatMessage{Role: msg.Role, Content: text})
+	}
+
+	if len(req.Messages) == 0 || req.Messages[len(req.Messages)-1].Role == "system" {
+		return req, fmt.Errorf("messages: at least one message is required")
+	}
+	return req, nil
+}
+
+// anthropicText joins text content blocks, rejecting block types Akash cannot handle
+func anthropicText(content model.AnthropicContent) (string, error) {
+	var text string
+	for i, block := range content {
+		if block.Type != "text" {
+			return "", fmt.Errorf("content block type %q is not supported", block.Type)
+		}
+		if i > 0 {
+			text += "\n"
+		}
+		text += block.Text
+	}
+	return text, nil
+}
+
+// anthropicStopReason maps a finish reason to the Anthropic stop reason and stop sequence
+func anthropicStopReason(finishReason, stopSequence string) (string, *string) {
+	switch {
+	case stopSequence != "":
+		return "stop_sequence", &stopSequence
+	case finishReason == service.FinishLength:
+		return "max_tokens", nil
+	case finishReason == "tool-calls" || finishReason == "tool_calls":
+		return "tool_use", nil
+	default:
+		return "end_turn", nil
+	}
+}
+
+// anthropicMessageID formats an upstream message ID as an Anthropic message ID
+func anthropicMessageID(messageID string) string {
+	if messageID == "" {
+		messageID = utils.GenerateRandomID(24)
+	}
+	return "msg_" + messageID
+}
+
+// writeAnthropicError writes an error in the Anthropic error format
+func writeAnthropicError(c *gin.Context, status int, message string) {
+	errorType := "api_error"
+	switch status {
+	case http.StatusBadRequest:
+		errorType = "invalid_request_error"
+	case http.StatusNotFound:
+		errorType = "not_found_error"
+	}
+
+	c.JSON(status, model.AnthropicErrorResponse{
+		Type:  "error",
+		Error: model.AnthropicError{Type: errorType, Message: message},
+	})
+}
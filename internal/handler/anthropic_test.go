@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+func TestMessages(t *testing.T) {
+	r, upstream := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1/messages", `{
+		"model": "Meta-Llama-3-3-70B-Instruct",
+		"max_tokens": 256,
+		"system": "Be brief.",
+		"messages": [{"role": "user", "content": [{"type": "text", "text": "hi"}]}]
+	}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var resp model.AnthropicMessagesResponse
+	decode(t, w, &resp)
+	if resp.Type != "message" || resp.Role != "assistant" || !strings.HasPrefix(resp.ID, "msg_") {
+		t.Errorf("type = %q, role = %q, id = %q", resp.Type, resp.Role, resp.ID)
+	}
+	if len(resp.Content) != 1 || resp.Content[0].Type != "text" || resp.Content[0].Text != "Hello world" {
+		t.Errorf("content = %+v, want one text block of Hello world", resp.Content)
+	}
+	if resp.StopReason == nil || *resp.StopReason != "end_turn" {
+		t.Errorf("stop_reason = %v, want end_turn", resp.StopReason)
+	}
+	if resp.Usage.InputTokens == 0 || resp.Usage.OutputTokens == 0 {
+		t.Errorf("usage = %+v, want token counts", resp.Usage)
+	}
+
+	// The system prompt is sent upstream as a leading system message
+	requests := upstream.chatRequests()
+	if len(requests) != 1 || len(requests[0].Messages) != 2 ||
+		requests[0].Messages[0].Role != "system" || requests[0].Messages[0].Content != "Be brief." {
+		t.Errorf("upstream requests = %+v", requests)
+	}
+}
+
+func TestMessagesStream(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1/messages",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","max_tokens":256,"stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var types []string
+	var text strings.Builder
+	for _, data := range sseData(t, w.Body.String()) {
+		var event model.AnthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("invalid event %q: %v", data, err)
+		}
+		types = append(types, event.Type)
+		if event.Type == "content_block_delta" {
+			text.WriteString(event.Delta.Text)
+		}
+	}
+
+	want := "message_start,content_block_start,ping,content_block_delta,content_block_delta,content_block_stop,message_delta,message_stop"
+	if got := strings.Join(types, ","); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+	if text.String() != "Hello world" {
+		t.Errorf("streamed text = %q, want Hello world", text.String())
+	}
+}
+
+func TestMessagesErrors(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		errorType string
+	}{
+		{"unknown model", `{"model":"Meta-Llama","max_tokens":256,"messages":[{"role":"user","content":"hi"}]}`, http.StatusNotFound, "not_found_error"},
+		{"system role in messages", `{"model":"Meta-Llama-3-3-70B-Instruct","max_tokens":256,"messages":[{"role":"system","content":"hi"}]}`, http.StatusBadRequest, "invalid_request_error"},
+		{"missing max_tokens", `{"model":"Meta-Llama-3-3-70B-Instruct","messages":[{"role":"user","content":"hi"}]}`, http.StatusBadRequest, "invalid_request_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodPost, "/v1/messages", tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.status, w.Body)
+			}
+			var resp model.AnthropicErrorResponse
+			decode(t, w, &resp)
+			if resp.Type != "error" || resp.Error.Type != tt.errorType {
+				t.Errorf("error = %+v, want type %s", resp, tt.errorType)
+			}
+		})
+	}
+}
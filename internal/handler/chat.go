@@ -1,29 +1,59 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/006lp/akashchat-api-go/internal/metrics"
 	"github.com/006lp/akashchat-api-go/internal/model"
 	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/006lp/akashchat-api-go/internal/utils"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ModelHeader reports the model that actually served a request, which differs
+// from the requested model when an alias or fallback was used
+const ModelHeader = "X-Akash-Model"
+
+// Context window headers report how a prompt was shortened to fit the model
+const (
+	ContextActionHeader  = "X-Context-Action"
+	ContextDroppedHeader = "X-Context-Dropped-Messages"
 )
 
 // ChatHandler handles chat-related HTTP requests
 type ChatHandler struct {
-	sessionService *service.SessionService
+	routerService  *service.RouterService
 	akashService   *service.AkashService
+	imageService   *service.ImageService
+	webhookService *service.WebhookService
+	responseStore  *service.ResponseStore
 }
 
 // NewChatHandler creates a new ChatHandler instance
-func NewChatHandler(sessionService *service.SessionService, akashService *service.AkashService) *ChatHandler {
+func NewChatHandler(routerService *service.RouterService, akashService *service.AkashService, imageService *service.ImageService, webhookService *service.WebhookService, responseStore *service.ResponseStore) *ChatHandler {
 	return &ChatHandler{
-		sessionService: sessionService,
+		routerService:  routerService,
 		akashService:   akashService,
+		imageService:   imageService,
+		webhookService: webhookService,
+		responseStore:  responseStore,
 	}
 }
 
 // ChatCompletions handles the /v1/chat/completions endpoint
 func (h *ChatHandler) ChatCompletions(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ChatHandler.ChatCompletions")
+	defer span.End()
+
 	var req model.ChatCompletionRequest
 
 	// Bind JSON request
@@ -35,33 +65,51 @@ func (h *ChatHandler) ChatCompletions(c *gin.Context) {
 		return
 	}
 
-	// Get session token
-	sessionToken, err := h.sessionService.GetSessionToken()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.APIResponse{
-			Code: 500,
-			Data: model.ErrorData{Message: "Failed to get session token: " + err.Error()},
+	// Resolve the model and its fallbacks
+	if e := h.routeModel(ctx, c, &req); e != nil {
+		writeAPIError(c, e)
+		return
+	}
+	defer h.recordModel(ctx, c, &req)
+
+	// Validate structured image parameters
+	if err := service.ValidateImageOptions(req.Image); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Code: 400,
+			Data: model.ErrorData{Message: "Invalid image parameters: " + err.Error()},
 		})
 		return
 	}
 
+	// Handle asynchronous requests that report back via callback URL
+	if req.CallbackURL != "" {
+		h.acceptAsync(c, req)
+		return
+	}
+
+	// Get session token, keeping text prompts within the model's context window
+	sessionToken, e := h.startRequest(ctx, c, &req)
+	if e != nil {
+		writeAPIError(c, e)
+		return
+	}
+
 	// Process chat request
 	if req.Model == "AkashGen" {
 		// Set default values
-		temperature := 0.85
-		if req.Temperature != nil {
-			temperature = *req.Temperature
-		}
-
-		topP := 1.0
-		if req.TopP != nil {
-			topP = *req.TopP
-		}
+		temperature, topP := service.SamplingParams(req)
 
 		// Handle image generation
-		data, err := h.akashService.ProcessImageGeneration(req, sessionToken, temperature, topP)
+		var images []*model.ImageGenerationData
+		err := h.routerService.WithFallback(ctx, &req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+			c.Header(ModelHeader, attempt.Model)
+			images, err = h.akashService.ProcessImageGenerations(ctx, attempt, sessionToken, temperature, topP)
+			return err
+		})
 		if err != nil {
-			if err.Error() == "invalid model" {
+			tracing.RecordError(span, err)
+			c.Error(err)
+			if errors.Is(err, service.ErrInvalidModel) {
 				c.JSON(http.StatusInternalServerError, model.APIResponse{
 					Code: 500,
 					Data: model.ErrorData{Message: "Error Model."},
@@ -75,21 +123,17 @@ func (h *ChatHandler) ChatCompletions(c *gin.Context) {
 			return
 		}
 
+		for _, image := range images {
+			logging.Set(ctx, logging.FieldUpstreamMessageID, image.JobID)
+		}
+		h.imageService.ProxyImages(h.imageService.BaseURL(c.Request), images)
 		c.JSON(http.StatusOK, model.APIResponse{
 			Code: 200,
-			Data: data,
+			Data: imageResult(images),
 		})
 	} else {
 		// Set default values
-		temperature := 0.6
-		if req.Temperature != nil {
-			temperature = *req.Temperature
-		}
-
-		topP := 0.95
-		if req.TopP != nil {
-			topP = *req.TopP
-		}
+		temperature, topP := service.SamplingParams(req)
 
 		// Handle text generation
 		if req.Stream != nil && *req.Stream && req.Model != "AkashGen" {
@@ -99,8 +143,12 @@ func (h *ChatHandler) ChatCompletions(c *gin.Context) {
 			c.Writer.Header().Set("Connection", "keep-alive")
 			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 
-			err := h.akashService.ProcessTextGenerationStream(req, sessionToken, temperature, topP, c.Writer)
+			err := h.routerService.WithFallback(ctx, &req, c.Writer.Written, func(attempt model.ChatCompletionRequest) error {
+				c.Header(ModelHeader, attempt.Model)
+				return h.akashService.ProcessTextGenerationStream(ctx, attempt, sessionToken, temperature, topP, c.Writer)
+			})
 			if err != nil {
+				tracing.RecordError(span, err)
 				// Since headers are already sent, we can't send a JSON error.
 				// Log the error and close the connection.
 				// A more robust solution might involve sending a specific error event in the stream.
@@ -109,9 +157,16 @@ func (h *ChatHandler) ChatCompletions(c *gin.Context) {
 			}
 		} else {
 			// Handle non-streaming
-			data, err := h.akashService.ProcessTextGeneration(req, sessionToken, temperature, topP)
+			var data *model.OpenAIChatCompletion
+			err := h.routerService.WithFallback(ctx, &req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+				c.Header(ModelHeader, attempt.Model)
+				data, err = h.akashService.ProcessTextGeneration(ctx, attempt, sessionToken, temperature, topP)
+				return err
+			})
 			if err != nil {
-				if err.Error() == "invalid model" {
+				tracing.RecordError(span, err)
+				c.Error(err)
+				if errors.Is(err, service.ErrInvalidModel) {
 					c.JSON(http.StatusInternalServerError, model.APIResponse{
 						Code: 500,
 						Data: model.ErrorData{Message: "Error Model."},
@@ -124,7 +179,210 @@ func (h *ChatHandler) ChatCompletions(c *gin.Context) {
 				})
 				return
 			}
+			logging.Set(ctx, logging.FieldUpstreamMessageID, strings.TrimPrefix(data.ID, "chatcmpl-"))
 			c.JSON(http.StatusOK, data)
 		}
 	}
 }
+
+// acceptAsync queues a request for background processing and responds immediately
+func (h *ChatHandler) acceptAsync(c *gin.Context, req model.ChatCompletionRequest) {
+	if err := h.webhookService.ValidateCallbackURL(c.Request.Context(), req.CallbackURL); err != nil {
+		message := "Invalid callback_url: " + err.Error()
+		switch {
+		case errors.Is(err, service.ErrWebhooksDisabled):
+			message = "callback_url is not supported: WEBHOOK_SECRET is not configured"
+		case errors.Is(err, service.ErrInvalidCallbackURL):
+			message = "Invalid callback_url: must be an absolute http(s) URL with a resolvable host"
+		}
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Code: 400,
+			Data: model.ErrorData{Message: message, Type: "invalid_request_error"},
+		})
+		return
+	}
+
+	if req.Stream != nil && *req.Stream {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Code: 400,
+			Data: model.ErrorData{Message: "Streaming is not supported with callback_url"},
+		})
+		return
+	}
+
+	jobID := "job-" + utils.GenerateRandomID(24)
+	go h.runAsync(context.WithoutCancel(c.Request.Context()), jobID, h.imageService.BaseURL(c.Request), req)
+
+	c.JSON(http.StatusAccepted, model.APIResponse{
+		Code: 202,
+		Data: model.AsyncJobData{
+			JobID:       jobID,
+			Status:      "queued",
+			CallbackURL: req.CallbackURL,
+		},
+	})
+}
+
+// runAsync processes a queued request and delivers the result to its callback URL.
+// Image URLs in the result point to the proxy at baseURL.
+func (h *ChatHandler) runAsync(ctx context.Context, jobID, baseURL string, req model.ChatCompletionRequest) {
+	payload := model.WebhookPayload{
+		ID:    jobID,
+		Model: req.Model,
+	}
+
+	data, err := h.generate(ctx, baseURL, &req)
+	payload.Model = req.Model
+	payload.Created = time.Now().Unix()
+	if err != nil {
+		payload.Event = "job.failed"
+		payload.Error = err.Error()
+	} else {
+		payload.Event = "job.completed"
+		payload.Data = data
+	}
+
+	h.webhookService.Dispatch(req.CallbackURL, payload)
+}
+
+// generate runs a non-streaming request to completion, leaving req.Model set to the model used
+func (h *ChatHandler) generate(ctx context.Context, baseURL string, req *model.ChatCompletionRequest) (interface{}, error) {
+	sessionToken, _, err := h.routerService.Prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Model == "AkashGen" {
+		temperature, topP := service.SamplingParams(*req)
+		var images []*model.ImageGenerationData
+		err := h.routerService.WithFallback(ctx, req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+			images, err = h.akashService.ProcessImageGenerations(ctx, attempt, sessionToken, temperature, topP)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		h.imageService.ProxyImages(baseURL, images)
+		return imageResult(images), nil
+	}
+
+	temperature, topP := service.SamplingParams(*req)
+	var data *model.OpenAIChatCompletion
+	err = h.routerService.WithFallback(ctx, req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+		data, err = h.akashService.ProcessTextGeneration(ctx, attempt, sessionToken, temperature, topP)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// chatError describes a failed chat request so that each API format can render it
+type chatError struct {
+	Status      int
+	Type        string
+	Message     string
+	Suggestions []string
+
+	// err is the underlying error, if any
+	err error
+}
+
+// writeAPIError writes e in the standard APIResponse format
+func writeAPIError(c *gin.Context, e *chatError) {
+	c.JSON(e.Status, model.APIResponse{
+		Code: e.Status,
+		Data: model.ErrorData{
+			Message:     e.Message,
+			Type:        e.Type,
+			Suggestions: e.Suggestions,
+		},
+	})
+}
+
+// routeModel resolves the model of req and labels the request with it
+func (h *ChatHandler) routeModel(ctx context.Context, c *gin.Context, req *model.ChatCompletionRequest) *chatError {
+	if e := h.resolveModel(ctx, req); e != nil {
+		return e
+	}
+	h.recordModel(ctx, c, req)
+	return nil
+}
+
+// resolveModel resolves aliases and virtual models, then selects the first model of
+// the fallback chain that the catalog reports as available
+func (h *ChatHandler) resolveModel(ctx context.Context, req *model.ChatCompletionRequest) *chatError {
+	requested := req.Model
+	if err := h.routerService.Route(ctx, req); err != nil {
+		return modelError(requested, err)
+	}
+
+	if len(req.Messages) > 0 {
+		logging.Set(ctx, logging.FieldPrompt, req.Messages[len(req.Messages)-1].Content)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("akash.stream", req.Stream != nil && *req.Stream))
+	return nil
+}
+
+// recordModel labels metrics and the request log with the model serving req
+func (h *ChatHandler) recordModel(ctx context.Context, c *gin.Context, req *model.ChatCompletionRequest) {
+	c.Set(metrics.ModelKey, req.Model)
+	logging.Set(ctx, logging.FieldModel, req.Model)
+}
+
+// startRequest prepares req and reports how its prompt was fitted into the
+// context window in response headers
+func (h *ChatHandler) startRequest(ctx context.Context, c *gin.Context, req *model.ChatCompletionRequest) (string, *chatError) {
+	sessionToken, result, e := h.prepareRequest(ctx, req)
+	if result.Action != "" {
+		c.Header(ContextActionHeader, result.Action)
+	}
+	if result.Dropped > 0 {
+		c.Header(ContextDroppedHeader, strconv.Itoa(result.Dropped))
+	}
+	if e != nil && e.err != nil {
+		c.Error(e.err)
+	}
+	return sessionToken, e
+}
+
+// prepareRequest gets a session token and fits text prompts into the model's
+// context window
+func (h *ChatHandler) prepareRequest(ctx context.Context, req *model.ChatCompletionRequest) (string, service.ContextResult, *chatError) {
+	sessionToken, result, err := h.routerService.Prepare(ctx, req)
+	if err != nil {
+		return "", result, prepareError(err)
+	}
+	return sessionToken, result, nil
+}
+
+// prepareError converts a failure to prepare a request to a chatError
+func prepareError(err error) *chatError {
+	var lengthErr *service.ContextLengthError
+	if errors.As(err, &lengthErr) {
+		return &chatError{
+			Status:  http.StatusBadRequest,
+			Type:    "context_length_exceeded",
+			Message: lengthErr.Error(),
+		}
+	}
+
+	var sessionErr *service.SessionError
+	if errors.As(err, &sessionErr) {
+		return &chatError{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to get session token: " + sessionErr.Err.Error(),
+			err:     err,
+		}
+	}
+	return &chatError{Status: http.StatusInternalServerError, Message: err.Error(), err: err}
+}
+
+// imageResult returns a single image when one was generated and the full list otherwise
+func imageResult(images []*model.ImageGenerationData) interface{} {
+	if len(images) == 1 {
+		return images[0]
+	}
+	return images
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/006lp/akashchat-api-go/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Completions handles the legacy /v1/completions endpoint. Each prompt is sent as
+// its own chat request and becomes the choice with the same index.
+func (h *ChatHandler) Completions(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ChatHandler.Completions")
+	defer span.End()
+
+	var creq model.CompletionRequest
+	if err := c.ShouldBindJSON(&creq); err != nil {
+		writeAPIError(c, &chatError{Status: http.StatusBadRequest, Message: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	if creq.Suffix != "" {
+		writeAPIError(c, &chatError{Status: http.StatusBadRequest, Message: "suffix is not supported"})
+		return
+	}
+	if len(creq.Prompt) == 0 {
+		writeAPIError(c, &chatError{Status: http.StatusBadRequest, Type: "invalid_request_error", Message: "prompt must not be empty"})
+		return
+	}
+
+	base := model.ChatCompletionRequest{
+		Model:       creq.Model,
+		Messages:    []model.ChatMessage{{Role: "user", Content: creq.Prompt[0]}},
+		Temperature: creq.Temperature,
+		TopP:        creq.TopP,
+		MaxTokens:   creq.MaxTokens,
+		Stop:        creq.Stop,
+		Stream:      &creq.Stream,
+	}
+
+	if e := h.routeModel(ctx, c, &base); e != nil {
+		writeAPIError(c, e)
+		return
+	}
+	defer h.recordModel(ctx, c, &base)
+
+	if base.Model == "AkashGen" {
+		writeAPIError(c, &chatError{Status: http.StatusBadRequest, Message: "Image generation is not supported by the completions API"})
+		return
+	}
+
+	temperature, topP := service.SamplingParams(base)
+	completion := model.TextCompletion{
+		ID:      "cmpl-" + utils.GenerateRandomID(24),
+		Object:  "text_completion",
+		Created: time.Now().Unix(),
+		Model:   base.Model,
+		Choices: []model.TextCompletionChoice{},
+	}
+	usage := model.Usage{}
+
+	for i, prompt := range creq.Prompt {
+		req := base
+		req.Messages = []model.ChatMessage{{Role: "user", Content: prompt}}
+
+		sessionToken, e := h.startRequest(ctx, c, &req)
+		if e != nil {
+			if !c.Writer.Written() {
+				writeAPIError(c, e)
+			}
+			return
+		}
+
+		if creq.Stream {
+			var echo string
+			if creq.Echo {
+				echo = prompt
+			}
+			last := i == len(creq.Prompt)-1
+			if !h.streamCompletion(ctx, c, req, sessionToken, temperature, topP, completion, i, echo, &usage, last) {
+				return
+			}
+			continue
+		}
+
+		var data *model.OpenAIChatCompletion
+		err := h.routerService.WithFallback(ctx, &req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+			c.Header(ModelHeader, attempt.Model)
+			data, err = h.akashService.ProcessTextGeneration(ctx, attempt, sessionToken, temperature, topP)
+			return err
+		})
+		if err != nil {
+			tracing.RecordError(span, err)
+			c.Error(err)
+			writeAPIError(c, &chatError{Status: http.StatusInternalServerError, Message: "Text generation failed: " + err.Error()})
+			return
+		}
+
+		text := data.Choices[0].Message.Content
+		if creq.Echo {
+			text = prompt + text
+		}
+		completion.Model = req.Model
+		completion.Choices = append(completion.Choices, model.TextCompletionChoice{
+			Text:         text,
+			Index:        i,
+			FinishReason: data.Choices[0].FinishReason,
+		})
+		addUsage(&usage, data.Usage)
+	}
+
+	if !creq.Stream {
+		completion.Usage = &usage
+		c.JSON(http.StatusOK, completion)
+	}
+}
+
+// streamCompletion streams the choice at index as text_completion chunks, starting
+// with the echoed prompt if any, and adds its token usage to usage. The total
+// usage is sent with the last choice. It reports whether the stream can continue.
+func (h *ChatHandler) streamCompletion(ctx context.Context, c *gin.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64, completion model.TextCompletion, index int, echo string, usage *model.Usage, last bool) bool {
+	chunk := func(modelID, text, finishReason string, usage *model.Usage) model.TextCompletion {
+		resp := completion
+		resp.Model = modelID
+		resp.Choices = []model.TextCompletionChoice{{Text: text, Index: index, FinishReason: finishReason}}
+		resp.Usage = usage
+		return resp
+	}
+
+	err := h.routerService.WithFallback(ctx, &req, c.Writer.Written, func(attempt model.ChatCompletionRequest) error {
+		c.Header(ModelHeader, attempt.Model)
+		return h.akashService.StreamTextGeneration(ctx, attempt, sessionToken, temperature, topP, func(event service.StreamEvent) error {
+			switch event.Type {
+			case service.StreamStart:
+				startSSE(c)
+				if echo != "" {
+					writeSSEData(c, chunk(attempt.Model, echo, "", nil))
+				}
+			case service.StreamDelta:
+				writeSSEData(c, chunk(attempt.Model, event.Content, "", nil))
+			case service.StreamFinish:
+				addUsage(usage, event.Usage)
+				var total *model.Usage
+				if last {
+					total = usage
+				}
+				writeSSEData(c, chunk(attempt.Model, "", event.FinishReason, total))
+			}
+			return nil
+		})
+	})
+	if err == nil {
+		return true
+	}
+
+	tracing.RecordError(trace.SpanFromContext(ctx), err)
+	c.Error(err)
+	if !c.Writer.Written() {
+		writeAPIError(c, &chatError{Status: http.StatusInternalServerError, Message: "Text generation failed: " + err.Error()})
+		return false
+	}
+	writeSSEData(c, model.APIResponse{
+		Code: http.StatusInternalServerError,
+		Data: model.ErrorData{Message: "Text generation failed: " + err.Error()},
+	})
+	return false
+}
+
+// addUsage adds the token counts of u to total
+func addUsage(total *model.Usage, u model.Usage) {
+	total.PromptTokens += u.PromptTokens
+	total.CompletionTokens += u.CompletionTokens
+	total.TotalTokens += u.TotalTokens
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCompletionsRejectsEmptyPrompt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/v1/completions", (&ChatHandler{}).Completions)
+
+	w := httptest.NewRecorder()
+	body := strings.NewReader(`{"model":"Meta-Llama-3-3-70B-Instruct","prompt":[]}`)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/completions", body))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var resp model.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(resp.Data)
+	var errData model.ErrorData
+	if err := json.Unmarshal(data, &errData); err != nil {
+		t.Fatal(err)
+	}
+	if errData.Type != "invalid_request_error" {
+		t.Errorf("type = %q, want invalid_request_error", errData.Type)
+	}
+}
+
+func TestCompletionsAnswersEachPrompt(t *testing.T) {
+	r, upstream := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1/completions",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","prompt":["Say","Greet"],"echo":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var resp model.TextCompletion
+	decode(t, w, &resp)
+	if resp.Object != "text_completion" || resp.Model != "Meta-Llama-3-3-70B-Instruct" {
+		t.Errorf("object = %q, model = %q", resp.Object, resp.Model)
+	}
+	if len(resp.Choices) != 2 {
+		t.Fatalf("got %d choices, want one per prompt", len(resp.Choices))
+	}
+	for i, want := range []string{"SayHello world", "GreetHello world"} {
+		if choice := resp.Choices[i]; choice.Index != i || choice.Text != want {
+			t.Errorf("choice %d = %+v, want the echoed prompt followed by the reply", i, choice)
+		}
+	}
+	if resp.Usage == nil || resp.Usage.CompletionTokens == 0 {
+		t.Errorf("usage = %+v, want completion tokens to be counted", resp.Usage)
+	}
+
+	// Each prompt is sent upstream as its own user message
+	requests := upstream.chatRequests()
+	if len(requests) != 2 || requests[1].Messages[0].Content != "Greet" {
+		t.Errorf("upstream requests = %+v", requests)
+	}
+}
+
+func TestCompletionsStream(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1/completions",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","prompt":"Say","stream":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var text strings.Builder
+	var last model.TextCompletion
+	for _, data := range sseData(t, w.Body.String()) {
+		if err := json.Unmarshal([]byte(data), &last); err != nil {
+			t.Fatalf("invalid chunk %q: %v", data, err)
+		}
+		for _, choice := range last.Choices {
+			text.WriteString(choice.Text)
+		}
+	}
+	if text.String() != "Hello world" {
+		t.Errorf("streamed text = %q, want Hello world", text.String())
+	}
+	if last.Choices[0].FinishReason != "stop" || last.Usage == nil {
+		t.Errorf("last chunk = %+v, want the finish reason and total usage", last)
+	}
+}
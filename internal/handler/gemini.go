@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GeminiGenerate handles the Gemini-compatible /v1beta/models/{model}:generateContent
+// and :streamGenerateContent endpoints. Streams use server-sent events when
+// alt=sse is given and a streamed JSON array otherwise, as Gemini does.
+func (h *ChatHandler) GeminiGenerate(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ChatHandler.GeminiGenerate")
+	defer span.End()
+
+	modelID, method, ok := strings.Cut(c.Param("action"), ":")
+	if !ok || (method != "generateContent" && method != "streamGenerateContent") {
+		writeGeminiError(c, http.StatusNotFound, "Method not found: "+c.Param("action"))
+		return
+	}
+
+	var greq model.GeminiRequest
+	if err := c.ShouldBindJSON(&greq); err != nil {
+		writeGeminiError(c, http.StatusBadRequest, "Invalid JSON payload: "+err.Error())
+		return
+	}
+
+	req, err := fromGemini(modelID, greq)
+	if err != nil {
+		writeGeminiError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if e := h.routeModel(ctx, c, &req); e != nil {
+		writeGeminiError(c, e.Status, e.Message)
+		return
+	}
+	defer h.recordModel(ctx, c, &req)
+
+	if req.Model == "AkashGen" {
+		writeGeminiError(c, http.StatusBadRequest, "Image generation is not supported by the Gemini API")
+		return
+	}
+
+	sessionToken, e := h.startRequest(ctx, c, &req)
+	if e != nil {
+		writeGeminiError(c, e.Status, e.Message)
+		return
+	}
+
+	temperature, topP := service.SamplingParams(req)
+
+	if method == "streamGenerateContent" {
+		h.streamGemini(ctx, c, req, sessionToken, temperature, topP, c.Query("alt") == "sse")
+		return
+	}
+
+	var generation *service.Generation
+	err = h.routerService.WithFallback(ctx, &req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+		c.Header(ModelHeader, attempt.Model)
+		generation, err = h.akashService.GenerateText(ctx, attempt, sessionToken, temperature, topP)
+		return err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		c.Error(err)
+		writeGeminiError(c, http.StatusInternalServerError, "Text generation failed: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, geminiResponse(req.Model, generation.MessageID, generation.Content, generation.FinishReason, &generation.Usage))
+}
+
+// streamGemini streams a generation as Gemini response chunks
+func (h *ChatHandler) streamGemini(ctx context.Context, c *gin.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64, sse bool) {
+	var responseID string
+	chunks := 0
+
+	write := func(resp model.GeminiResponse) {
+		if sse {
+			writeSSEData(c, resp)
+			return
+		}
+
+		// Without alt=sse the chunks form a single JSON array
+		if chunks == 0 {
+			c.Writer.WriteString("[")
+		} else {
+			c.Writer.WriteString(",\r\n")
+		}
+		writeNDJSON(c, resp)
+		chunks++
+	}
+
+	err := h.routerService.WithFallback(ctx, &req, c.Writer.Written, func(attempt model.ChatCompletionRequest) error {
+		c.Header(ModelHeader, attempt.Model)
+		return h.akashService.StreamTextGeneration(ctx, attempt, sessionToken, temperature, topP, func(event service.StreamEvent) error {
+			switch event.Type {
+			case service.StreamStart:
+				responseID = event.MessageID
+				if sse {
+					startSSE(c)
+				} else {
+					c.Writer.Header().Set("Content-Type", "application/json")
+				}
+			case service.StreamDelta:
+				write(geminiResponse(attempt.Model, responseID, event.Content, "", nil))
+			case service.StreamFinish:
+				write(geminiResponse(attempt.Model, responseID, "", event.FinishReason, &event.Usage))
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		tracing.RecordError(trace.SpanFromContext(ctx), err)
+		c.Error(err)
+		if !c.Writer.Written() {
+			writeGeminiError(c, http.StatusInternalServerError, "Text generation failed: "+err.Error())
+			return
+		}
+
+		failure := model.GeminiErrorResponse{
+			Error: model.GeminiError{Code: http.StatusInternalServerError, Message: err.Error(), Status: "INTERNAL"},
+		}
+		if sse {
+			writeSSEData(c, failure)
+		} else {
+			c.Writer.WriteString(",\r\n")
+			writeNDJSON(c, failure)
+		}
+	}
+
+	if !sse && chunks > 0 {
+		c.Writer.WriteString("]")
+		c.Writer.Flush()
+	}
+}
+
+// fromGemini converts a Gemini request to a chat completion request. The system
+// instruction becomes a leading system message.
+func fromGemini(modelID string, greq model.GeminiRequest) (model.ChatCompletionRequest, error) {
+	req := model.ChatCompletionRequest{Model: strings.TrimPrefix(modelID, "models/")}
+
+	system := greq.SystemInstruction
+	if system == nil {
+		system = greq.SystemInstructionSnake
+	}
+	if system != nil {
+		text, err := geminiText(*system)
+		if err != nil {
+			return req, err
+		}
+		req.Messages = append(req.Messages, model.ChatMessage{Role: "system", Content: text})
+	}
+
+	for _, content := range greq.Contents {
+		role := "user"
+		switch content.Role {
+		case "", "user":
+		case "model":
+			role = "assistant"
+		default:
+			return req, fmt.Errorf("Please use a valid role: user, model.")
+		}
+
+		text, err := geminiText(content)
+		if err != nil {
+			return req, err
+		}
+		req.Messages = append(req.Messages, model.ChatMessage{Role: role, Content: text})
+	}
+
+	if len(req.Messages) == 0 || req.Messages[len(req.Messages)-1].Role == "system" {
+		return req, fmt.Errorf("* GenerateContentRequest.contents: contents is not specified")
+	}
+
+	config := greq.GenerationConfig
+	if config == nil {
+		config = greq.GenerationConfigSnake
+	}
+	if config != nil {
+		if config.CandidateCount > 1 {
+			return req, fmt.Errorf("Only one candidate can be specified")
+		}
+		req.Temperature = config.Temperature
+		req.TopP = config.TopP
+		req.MaxTokens = config.MaxOutputTokens
+		req.Stop = config.StopSequences
+	}
+
+	return req, nil
+}
+
+// geminiText joins the text parts of content, rejecting parts Akash cannot handle
+func geminiText(content model.GeminiContent) (string, error) {
+	var texts []string
+	for _, part := range content.Parts {
+		if part.InlineData != nil || part.FileData != nil || part.FunctionCall != nil || part.FunctionResponse != nil {
+			return "", fmt.Errorf("only text parts are supported")
+		}
+		texts = append(texts, part.Text)
+	}
+	return strings.Join(texts, ""), nil
+}
+
+// geminiResponse builds a Gemini response or stream chunk
+func geminiResponse(modelID, responseID, text, finishReason string, usage *model.Usage) model.GeminiResponse {
+	candidate := model.GeminiCandidate{
+		Content: model.GeminiContent{
+			Role:  "model",
+			Parts: []model.GeminiPart{{Text: text}},
+		},
+	}
+	if usage != nil {
+		candidate.FinishReason = "STOP"
+		if finishReason == service.FinishLength {
+			candidate.FinishReason = "MAX_TOKENS"
+		}
+	}
+
+	resp := model.GeminiResponse{
+		Candidates:   []model.GeminiCandidate{candidate},
+		ModelVersion: modelID,
+		ResponseID:   responseID,
+	}
+	if usage != nil {
+		resp.UsageMetadata = &model.GeminiUsageMetadata{
+			PromptTokenCount:     usage.PromptTokens,
+			CandidatesTokenCount: usage.CompletionTokens,
+			TotalTokenCount:      usage.TotalTokens,
+		}
+	}
+	return resp
+}
+
+// writeGeminiError writes an error in the Gemini error format
+func writeGeminiError(c *gin.Context, status int, message string) {
+	statusName := "INTERNAL"
+	switch status {
+	case http.StatusBadRequest:
+		statusName = "INVALID_ARGUMENT"
+	case http.StatusNotFound:
+		statusName = "NOT_FOUND"
+	}
+
+	c.JSON(status, model.GeminiErrorResponse{
+		Error: model.GeminiError{Code: status, Message: message, Status: statusName},
+	})
+}
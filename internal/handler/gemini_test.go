@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+// geminiResponseText joins the text of every candidate part in responses
+func geminiResponseText(responses []model.GeminiResponse) string {
+	var text strings.Builder
+	for _, resp := range responses {
+		for _, candidate := range resp.Candidates {
+			for _, part := range candidate.Content.Parts {
+				text.WriteString(part.Text)
+			}
+		}
+	}
+	return text.String()
+}
+
+func TestGeminiGenerateContent(t *testing.T) {
+	r, upstream := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1beta/models/Meta-Llama-3-3-70B-Instruct:generateContent", `{
+		"systemInstruction": {"parts": [{"text": "Be brief."}]},
+		"contents": [
+			{"role": "user", "parts": [{"text": "hi"}]},
+			{"role": "model", "parts": [{"text": "hello"}]},
+			{"parts": [{"text": "again"}]}
+		],
+		"generationConfig": {"temperature": 0.2}
+	}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var resp model.GeminiResponse
+	decode(t, w, &resp)
+	if text := geminiResponseText([]model.GeminiResponse{resp}); text != "Hello world" {
+		t.Errorf("text = %q, want Hello world", text)
+	}
+	if resp.Candidates[0].Content.Role != "model" || resp.Candidates[0].FinishReason != "STOP" {
+		t.Errorf("candidate = %+v, want a finished model candidate", resp.Candidates[0])
+	}
+	if resp.ModelVersion != "Meta-Llama-3-3-70B-Instruct" || resp.UsageMetadata == nil || resp.UsageMetadata.TotalTokenCount == 0 {
+		t.Errorf("modelVersion = %q, usageMetadata = %+v", resp.ModelVersion, resp.UsageMetadata)
+	}
+
+	// Gemini roles map onto chat roles, with the system instruction first
+	requests := upstream.chatRequests()
+	if len(requests) != 1 {
+		t.Fatalf("upstream received %d requests, want 1", len(requests))
+	}
+	var roles []string
+	for _, msg := range requests[0].Messages {
+		roles = append(roles, msg.Role)
+	}
+	if got := strings.Join(roles, ","); got != "system,user,assistant,user" {
+		t.Errorf("upstream roles = %s", got)
+	}
+}
+
+func TestGeminiStreamGenerateContent(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+	body := `{"contents":[{"parts":[{"text":"hi"}]}]}`
+
+	// With alt=sse every chunk is a server-sent event
+	w := serve(r, http.MethodPost, "/v1beta/models/Meta-Llama-3-3-70B-Instruct:streamGenerateContent?alt=sse", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	var events []model.GeminiResponse
+	for _, data := range sseData(t, w.Body.String()) {
+		var resp model.GeminiResponse
+		if err := json.Unmarshal([]byte(data), &resp); err != nil {
+			t.Fatalf("invalid event %q: %v", data, err)
+		}
+		events = append(events, resp)
+	}
+	if text := geminiResponseText(events); text != "Hello world" {
+		t.Errorf("streamed text = %q, want Hello world", text)
+	}
+	if last := events[len(events)-1]; last.UsageMetadata == nil || last.Candidates[0].FinishReason != "STOP" {
+		t.Errorf("last chunk = %+v, want the finish reason and usage", last)
+	}
+
+	// Without it the chunks form one JSON array
+	w = serve(r, http.MethodPost, "/v1beta/models/Meta-Llama-3-3-70B-Instruct:streamGenerateContent", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	var chunks []model.GeminiResponse
+	decode(t, w, &chunks)
+	if text := geminiResponseText(chunks); text != "Hello world" {
+		t.Errorf("streamed text = %q, want Hello world", text)
+	}
+}
+
+func TestGeminiGenerateErrors(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status string
+	}{
+		{"unknown method", "/v1beta/models/Meta-Llama-3-3-70B-Instruct:embedContent", `{}`, "NOT_FOUND"},
+		{"unknown model", "/v1beta/models/gemini-pro:generateContent", `{"contents":[{"parts":[{"text":"hi"}]}]}`, "NOT_FOUND"},
+		{"invalid role", "/v1beta/models/Meta-Llama-3-3-70B-Instruct:generateContent", `{"contents":[{"role":"tool","parts":[{"text":"hi"}]}]}`, "INVALID_ARGUMENT"},
+		{"no contents", "/v1beta/models/Meta-Llama-3-3-70B-Instruct:generateContent", `{"contents":[]}`, "INVALID_ARGUMENT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodPost, tt.path, tt.body)
+			var resp model.GeminiErrorResponse
+			decode(t, w, &resp)
+			if resp.Error.Status != tt.status || resp.Error.Code != w.Code {
+				t.Errorf("status = %d, error = %+v, want %s", w.Code, resp.Error, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/config"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+	"github.com/gin-gonic/gin"
+)
+
+// testModels is the catalog served by the fake upstream
+var testModels = []akash.Model{
+	{ID: "Meta-Llama-3-3-70B-Instruct", Name: "Llama 3.3 70B", TokenLimit: 128000, Available: true},
+	{ID: "Qwen3-235B-A22B-Instruct-2507-FP8", Name: "Qwen3 235B", TokenLimit: 32000, Available: true},
+	{ID: "Broken-Model", Name: "Broken", TokenLimit: 8000, Available: true},
+	{ID: "Small-Model", Name: "Small", TokenLimit: 2000, Available: true},
+	{ID: "Slow-Model", Name: "Slow", TokenLimit: 8000, Available: true},
+	{ID: "Retired-Model", Name: "Retired", TokenLimit: 8000, Available: false},
+	{ID: "AkashGen", Name: "AkashGen", Available: true},
+}
+
+// fakeUpstream imitates the Akash Chat API. Chat requests for Broken-Model fail
+// with a 502 so that fallbacks can be exercised, and those for Slow-Model start
+// a message that never finishes so that cancellation can be exercised.
+type fakeUpstream struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	requests []upstreamChatRequest
+}
+
+// upstreamChatRequest is the part of an upstream chat request the tests inspect
+type upstreamChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []akash.Message `json:"messages"`
+}
+
+// newFakeUpstream starts a fake Akash Chat API
+func newFakeUpstream(t *testing.T) *fakeUpstream {
+	t.Helper()
+
+	f := &fakeUpstream{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/session/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session_token=test-session; Path=/")
+	})
+	mux.HandleFunc("/api/models/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(testModels)
+	})
+	mux.HandleFunc("/api/chat/", func(w http.ResponseWriter, r *http.Request) {
+		var req upstreamChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.mutex.Lock()
+		f.requests = append(f.requests, req)
+		f.mutex.Unlock()
+
+		if req.Model == "Broken-Model" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if req.Model == "Slow-Model" {
+			w.Write([]byte("f:{\"messageId\":\"msg-slow\"}\n"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("f:{\"messageId\":\"msg-test\"}\n0:\"Hello\"\n0:\" world\"\ne:{\"finishReason\":\"stop\"}\n"))
+	})
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// chatRequests returns the chat requests the upstream received
+func (f *fakeUpstream) chatRequests() []upstreamChatRequest {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]upstreamChatRequest(nil), f.requests...)
+}
+
+// newTestRouter wires the chat handlers to a fake upstream behind middleware
+func newTestRouter(t *testing.T, modelConfig *config.ModelConfig, middleware ...gin.HandlerFunc) (*gin.Engine, *fakeUpstream) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	upstream := newFakeUpstream(t)
+	if modelConfig == nil {
+		modelConfig = &config.ModelConfig{}
+	}
+
+	client := akash.New(akash.WithBaseURL(upstream.URL))
+	sessionService := service.NewSessionService(client)
+	catalogService := service.NewCatalogService(client, 0)
+	akashService := service.NewAkashService(client, catalogService, nil)
+	aliasService := service.NewAliasService(modelConfig)
+	contextService := service.NewContextService(catalogService, akashService, "truncate", "", 1024)
+	routerService := service.NewRouterService(sessionService, catalogService, aliasService, contextService)
+	chatHandler := NewChatHandler(routerService, akashService, service.NewImageService(client, 0, 0, "", ""), service.NewWebhookService("", 1, ""), service.NewResponseStore(10))
+
+	r := gin.New()
+	r.Use(middleware...)
+	r.POST("/v1/chat/completions", chatHandler.ChatCompletions)
+	r.POST("/v1/completions", chatHandler.Completions)
+	r.POST("/v1/messages", chatHandler.Messages)
+	r.POST("/v1/responses", chatHandler.Responses)
+	r.GET("/v1/responses/:id", chatHandler.GetResponse)
+	r.DELETE("/v1/responses/:id", chatHandler.DeleteResponse)
+	r.POST("/v1beta/models/:action", chatHandler.GeminiGenerate)
+	r.POST("/api/chat", chatHandler.OllamaChat)
+	r.POST("/api/generate", chatHandler.OllamaGenerate)
+	r.GET("/v1/ws/chat", chatHandler.WebSocketChat)
+	r.POST("/v1/tokenize", NewTokenizeHandler(catalogService, aliasService).Tokenize)
+	return r, upstream
+}
+
+// serve sends a JSON request to r and returns the recorded response
+func serve(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decode unmarshals the body of w into v
+func decode(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", w.Body.String(), err)
+	}
+}
+
+func TestChatCompletionsRoutesAliasAndFallback(t *testing.T) {
+	r, upstream := newTestRouter(t, &config.ModelConfig{
+		Aliases:   map[string]string{"fast": "Broken-Model"},
+		Fallbacks: map[string][]string{"Broken-Model": {"Retired-Model", "Meta-Llama-3-3-70B-Instruct"}},
+	})
+
+	w := serve(r, http.MethodPost, "/v1/chat/completions",
+		`{"model":"fast","messages":[{"role":"user","content":"hi"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get(ModelHeader); got != "Meta-Llama-3-3-70B-Instruct" {
+		t.Errorf("%s = %q, want the fallback model", ModelHeader, got)
+	}
+
+	var resp struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	decode(t, w, &resp)
+	if len(resp.Choices) != 1 || resp.Choices[0].Message.Content != "Hello world" {
+		t.Errorf("choices = %+v, want one reply of Hello world", resp.Choices)
+	}
+
+	// The unavailable model in the chain is skipped without calling upstream
+	var models []string
+	for _, req := range upstream.chatRequests() {
+		models = append(models, req.Model)
+	}
+	if strings.Join(models, ",") != "Broken-Model,Meta-Llama-3-3-70B-Instruct" {
+		t.Errorf("upstream models = %v", models)
+	}
+}
+
+func TestChatCompletionsUnknownModel(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1/chat/completions",
+		`{"model":"Meta-Llama-3-3-70B","messages":[{"role":"user","content":"hi"}]}`)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if !strings.Contains(w.Body.String(), "Meta-Llama-3-3-70B-Instruct") {
+		t.Errorf("body %s does not suggest the closest model", w.Body)
+	}
+}
+
+func TestChatCompletionsRefitsPromptForFallback(t *testing.T) {
+	r, upstream := newTestRouter(t, &config.ModelConfig{
+		Fallbacks: map[string][]string{"Broken-Model": {"Small-Model"}},
+	})
+
+	long := strings.Repeat("word ", 1500)
+	body, _ := json.Marshal(map[string]any{
+		"model": "Broken-Model",
+		"messages": []map[string]string{
+			{"role": "user", "content": long},
+			{"role": "assistant", "content": long},
+			{"role": "user", "content": "and now?"},
+		},
+	})
+	w := serve(r, http.MethodPost, "/v1/chat/completions", string(body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	// The prompt fits the first model but has to be truncated for the fallback
+	requests := upstream.chatRequests()
+	if len(requests) != 2 {
+		t.Fatalf("upstream received %d requests, want 2", len(requests))
+	}
+	if n := len(requests[0].Messages); n != 3 {
+		t.Errorf("%s received %d messages, want 3", requests[0].Model, n)
+	}
+	if n := len(requests[1].Messages); n != 1 || requests[1].Messages[0].Content != "and now?" {
+		t.Errorf("%s received %d messages, want only the last one", requests[1].Model, n)
+	}
+}
+
+// sseData returns the data fields of the server-sent events in body
+func sseData(t *testing.T, body string) []string {
+	t.Helper()
+	var data []string
+	for _, line := range strings.Split(body, "\n") {
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimSpace(value))
+		}
+	}
+	if len(data) == 0 {
+		t.Fatalf("no server-sent events in %q", body)
+	}
+	return data
+}
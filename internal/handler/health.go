@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	healthService *service.HealthService
+}
+
+// NewHealthHandler creates a new HealthHandler instance
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Livez handles the /livez endpoint
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": service.HealthOK,
+	})
+}
+
+// Readyz handles the /readyz endpoint
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.healthService.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == service.HealthFail {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/metrics"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ImageHandler handles image-related HTTP requests
+type ImageHandler struct {
+	routerService *service.RouterService
+	akashService  *service.AkashService
+	imageService  *service.ImageService
+}
+
+// NewImageHandler creates a new ImageHandler instance
+func NewImageHandler(routerService *service.RouterService, akashService *service.AkashService, imageService *service.ImageService) *ImageHandler {
+	return &ImageHandler{
+		routerService: routerService,
+		akashService:  akashService,
+		imageService:  imageService,
+	}
+}
+
+// CreateImage handles the /v1/images/generations endpoint
+func (h *ImageHandler) CreateImage(c *gin.Context) {
+	var req model.ImageGenerationRequest
+
+	// Bind JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Code: 400,
+			Data: model.ErrorData{Message: "Invalid request format: " + err.Error()},
+		})
+		return
+	}
+
+	opts := req.ImageOptions
+	if err := service.ValidateImageOptions(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Code: 400,
+			Data: model.ErrorData{Message: "Invalid image parameters: " + err.Error()},
+		})
+		return
+	}
+
+	if req.Model == "" {
+		req.Model = "AkashGen"
+	}
+
+	// Resolve aliases and reject unknown or unavailable models before calling upstream
+	chatReq, err := h.routerService.Image(c.Request.Context(), req.Model, req.Prompt, &opts)
+	c.Set(metrics.ModelKey, chatReq.Model)
+	if err != nil {
+		writeAPIError(c, modelError(req.Model, err))
+		return
+	}
+
+	// Get session token
+	sessionToken, _, err := h.routerService.Prepare(c.Request.Context(), &chatReq)
+	if err != nil {
+		writeAPIError(c, prepareError(err))
+		return
+	}
+
+	temperature, topP := service.SamplingParams(chatReq)
+	images, err := h.akashService.ProcessImageGenerations(c.Request.Context(), chatReq, sessionToken, temperature, topP)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidModel) {
+			c.JSON(http.StatusInternalServerError, model.APIResponse{
+				Code: 500,
+				Data: model.ErrorData{Message: "Error Model."},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Code: 500,
+			Data: model.ErrorData{Message: "Image generation failed: " + err.Error()},
+		})
+		return
+	}
+
+	h.imageService.ProxyImages(h.imageService.BaseURL(c.Request), images)
+	resp := model.OpenAIImagesResponse{
+		Created: time.Now().Unix(),
+		Data:    make([]model.OpenAIImage, 0, len(images)),
+	}
+	for _, image := range images {
+		resp.Data = append(resp.Data, model.OpenAIImage{
+			URL:            image.Pic,
+			RevisedPrompt:  image.Prompt,
+			OriginalPrompt: image.OriginalPrompt,
+			JobID:          image.JobID,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetImage handles the /v1/images/:name endpoint
+func (h *ImageHandler) GetImage(c *gin.Context) {
+	width := 0
+	if value := c.Query("width"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Code: 400,
+				Data: model.ErrorData{Message: "Invalid width: " + value},
+			})
+			return
+		}
+		width = n
+	}
+
+	file, err := h.imageService.GetImage(c.Request.Context(), c.Param("name"), c.Query("format"), width)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidImageName),
+			errors.Is(err, service.ErrUnsupportedImageFormat),
+			errors.Is(err, service.ErrInvalidImageWidth):
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Code: 400,
+				Data: model.ErrorData{Message: err.Error()},
+			})
+		case errors.Is(err, service.ErrImageNotFound):
+			c.JSON(http.StatusNotFound, model.APIResponse{
+				Code: 404,
+				Data: model.ErrorData{Message: err.Error()},
+			})
+		case errors.Is(err, service.ErrImageTooLarge):
+			c.JSON(http.StatusBadGateway, model.APIResponse{
+				Code: 502,
+				Data: model.ErrorData{Message: err.Error()},
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, model.APIResponse{
+				Code: 500,
+				Data: model.ErrorData{Message: "Failed to load image: " + err.Error()},
+			})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
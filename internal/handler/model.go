@@ -1,70 +1,205 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"io"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 // ModelHandler handles model-related HTTP requests
-type ModelHandler struct{}
+type ModelHandler struct {
+	catalogService *service.CatalogService
+	aliasService   *service.AliasService
+}
 
 // NewModelHandler creates a new ModelHandler instance
-func NewModelHandler() *ModelHandler {
-	return &ModelHandler{}
+func NewModelHandler(catalogService *service.CatalogService, aliasService *service.AliasService) *ModelHandler {
+	return &ModelHandler{
+		catalogService: catalogService,
+		aliasService:   aliasService,
+	}
 }
 
-// GetModels handles the /v1/models endpoint
+// modelCreated is the creation timestamp reported for every model, as Akash does not provide one
+const modelCreated = 1755000000
+
+// GetModels handles the /v1/models endpoint. Aliases and virtual models are
+// listed after the real models. Passing ?extended=true returns every catalog
+// model with its Akash metadata and derived capabilities. The ETag is computed
+// from the response body, so it changes whenever the listed models or aliases do.
 func (h *ModelHandler) GetModels(c *gin.Context) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", "https://chat.akash.network/api/models/", nil)
+	models, err := h.catalogService.Models(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch models"})
 		return
 	}
 
-	req.Header.Set("Referer", "https://chat.akash.network/")
-	req.Header.Set("Accept", "*/*")
+	var list interface{}
+	if c.Query("extended") == "true" {
+		extendedModels := make([]model.ExtendedModel, 0, len(models))
+		for _, m := range models {
+			extendedModels = append(extendedModels, toExtendedModel(m))
+		}
+		for _, alias := range h.aliasService.Aliases() {
+			if target, ok := findModel(models, alias.Target); ok {
+				extendedModels = append(extendedModels, toAliasModel(alias, target))
+			}
+		}
+		list = model.ExtendedModelsList{
+			Object: "list",
+			Data:   extendedModels,
+		}
+	} else {
+		var openAIModels []model.OpenAIModel
+		for _, m := range models {
+			if m.Available {
+				openAIModels = append(openAIModels, toOpenAIModel(m))
+			}
+		}
+		for _, alias := range h.aliasService.Aliases() {
+			if target, ok := findModel(models, alias.Target); ok && target.Available {
+				openAIModels = append(openAIModels, toAliasModel(alias, target).OpenAIModel)
+			}
+		}
+		list = model.OpenAIModelsList{
+			Object: "list",
+			Data:   openAIModels,
+		}
+	}
 
-	resp, err := client.Do(req)
+	body, err := json.Marshal(list)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch models"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode models"})
 		return
 	}
-	defer resp.Body.Close()
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatches reports whether an If-None-Match header matches etag. The header
+// may list several tags, weak ones included, or be "*".
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetModel handles the /v1/models/:id endpoint
+func (h *ModelHandler) GetModel(c *gin.Context) {
+	id := c.Param("id")
+	alias, isAlias := h.aliasService.Lookup(id)
+
+	lookupID := id
+	if isAlias {
+		lookupID = alias.Target
+	}
 
-	body, err := io.ReadAll(resp.Body)
+	m, ok, err := h.catalogService.Lookup(c.Request.Context(), lookupID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response body"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch models"})
 		return
 	}
 
-	var models []model.Model
-	if err := json.Unmarshal(body, &models); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unmarshal models"})
+	if !ok {
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Code: 404,
+			Data: model.ErrorData{
+				Message: "The model '" + id + "' does not exist.",
+				Type:    "model_not_found",
+			},
+		})
 		return
 	}
 
-	var openAIModels []model.OpenAIModel
+	if isAlias {
+		c.JSON(http.StatusOK, toAliasModel(alias, m))
+		return
+	}
+	c.JSON(http.StatusOK, toExtendedModel(m))
+}
+
+// findModel returns the catalog model with the given id
+func findModel(models []model.Model, id string) (model.Model, bool) {
 	for _, m := range models {
-		if m.Available {
-			openAIModels = append(openAIModels, model.OpenAIModel{
-				ID:         m.ID,
-				Object:     "model",
-				Created:    1755000000,
-				OwnedBy:    "Akash Network",
-				Permission: nil,
-				Root:       m.ID,
-				Parent:     nil,
-			})
+		if m.ID == id {
+			return m, true
 		}
 	}
+	return model.Model{}, false
+}
+
+// toOpenAIModel converts a catalog model to the OpenAI model format
+func toOpenAIModel(m model.Model) model.OpenAIModel {
+	return model.OpenAIModel{
+		ID:         m.ID,
+		Object:     "model",
+		Created:    modelCreated,
+		OwnedBy:    "Akash Network",
+		Permission: nil,
+		Root:       m.ID,
+		Parent:     nil,
+	}
+}
 
-	c.JSON(http.StatusOK, model.OpenAIModelsList{
-		Object: "list",
-		Data:   openAIModels,
-	})
+// toExtendedModel converts a catalog model to the extended model format
+func toExtendedModel(m model.Model) model.ExtendedModel {
+	return model.ExtendedModel{
+		OpenAIModel:  toOpenAIModel(m),
+		Name:         m.Name,
+		Description:  m.Description,
+		TokenLimit:   m.TokenLimit,
+		Parameters:   m.Parameters,
+		Architecture: m.Architecture,
+		HFRepo:       m.HFRepo,
+		Available:    m.Available,
+		Capabilities: service.ModelCapabilities(m),
+	}
+}
+
+// toAliasModel describes an alias or virtual model using its target's metadata
+func toAliasModel(alias service.ModelAlias, target model.Model) model.ExtendedModel {
+	m := toExtendedModel(target)
+	m.ID = alias.ID
+	m.OwnedBy = "akashchat-api-go"
+	m.Parent = target.ID
+	if alias.Description != "" {
+		m.Description = alias.Description
+	}
+	return m
+}
+
+// modelError converts a routing failure for the requested modelID to a chatError
+func modelError(modelID string, err error) *chatError {
+	var notFound *service.ModelNotFoundError
+	if errors.As(err, &notFound) {
+		return &chatError{
+			Status:      http.StatusNotFound,
+			Type:        "model_not_found",
+			Message:     "The model '" + modelID + "' does not exist or is not available.",
+			Suggestions: notFound.Suggestions,
+		}
+	}
+
+	return &chatError{
+		Status:  http.StatusInternalServerError,
+		Message: "Failed to validate model: " + err.Error(),
+	}
 }
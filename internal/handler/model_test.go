@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/config"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+	"github.com/gin-gonic/gin"
+)
+
+// newModelRouter serves /v1/models from a fake upstream with the given aliases
+func newModelRouter(t *testing.T, upstream *fakeUpstream, aliases map[string]string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	client := akash.New(akash.WithBaseURL(upstream.URL))
+	modelHandler := NewModelHandler(service.NewCatalogService(client, 0), service.NewAliasService(&config.ModelConfig{Aliases: aliases}))
+
+	r := gin.New()
+	r.GET("/v1/models", modelHandler.GetModels)
+	return r
+}
+
+// getModels requests /v1/models with an optional If-None-Match header
+func getModels(r http.Handler, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetModelsETagCoversAliases(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	plain := getModels(newModelRouter(t, upstream, nil), "/v1/models", "")
+	aliased := getModels(newModelRouter(t, upstream, map[string]string{"fast": "Qwen3-235B-A22B-Instruct-2507-FP8"}), "/v1/models", "")
+
+	if plain.Code != http.StatusOK || aliased.Code != http.StatusOK {
+		t.Fatalf("status = %d and %d", plain.Code, aliased.Code)
+	}
+	if plain.Header().Get("ETag") == aliased.Header().Get("ETag") {
+		t.Error("adding an alias did not change the ETag")
+	}
+}
+
+func TestGetModelsIfNoneMatch(t *testing.T) {
+	r := newModelRouter(t, newFakeUpstream(t), nil)
+	etag := getModels(r, "/v1/models", "").Header().Get("ETag")
+	extendedETag := getModels(r, "/v1/models?extended=true", "").Header().Get("ETag")
+	if etag == "" || etag == extendedETag {
+		t.Fatalf("ETag = %q, extended ETag = %q", etag, extendedETag)
+	}
+
+	tests := []struct {
+		header string
+		want   int
+	}{
+		{etag, http.StatusNotModified},
+		{"W/" + etag, http.StatusNotModified},
+		{`"other", ` + etag, http.StatusNotModified},
+		{"*", http.StatusNotModified},
+		{`"other"`, http.StatusOK},
+		{extendedETag, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if w := getModels(r, "/v1/models", tt.header); w.Code != tt.want {
+			t.Errorf("If-None-Match %s: status = %d, want %d", tt.header, w.Code, tt.want)
+		}
+	}
+}
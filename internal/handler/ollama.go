@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ollamaVersion is the Ollama API version this proxy reports compatibility with
+const ollamaVersion = "0.6.0"
+
+// OllamaChat handles the Ollama-compatible /api/chat endpoint
+func (h *ChatHandler) OllamaChat(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ChatHandler.OllamaChat")
+	defer span.End()
+
+	var oreq model.OllamaChatRequest
+	if err := c.ShouldBindJSON(&oreq); err != nil {
+		writeOllamaError(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	req := model.ChatCompletionRequest{Model: ollamaModelName(oreq.Model)}
+	for _, msg := range oreq.Messages {
+		if len(msg.Images) > 0 {
+			writeOllamaError(c, http.StatusBadRequest, "images are not supported")
+			return
+		}
+		req.Messages = append(req.Messages, model.ChatMessage{Role: msg.Role, Content: msg.Content})
+	}
+	applyOllamaOptions(&req, oreq.Options)
+
+	h.serveOllama(ctx, c, req, oreq.Stream == nil || *oreq.Stream, true)
+}
+
+// OllamaGenerate handles the Ollama-compatible /api/generate endpoint
+func (h *ChatHandler) OllamaGenerate(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ChatHandler.OllamaGenerate")
+	defer span.End()
+
+	var oreq model.OllamaGenerateRequest
+	if err := c.ShouldBindJSON(&oreq); err != nil {
+		writeOllamaError(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	if len(oreq.Images) > 0 {
+		writeOllamaError(c, http.StatusBadRequest, "images are not supported")
+		return
+	}
+
+	req := model.ChatCompletionRequest{Model: ollamaModelName(oreq.Model)}
+	if oreq.System != "" {
+		req.Messages = append(req.Messages, model.ChatMessage{Role: "system", Content: oreq.System})
+	}
+	if oreq.Prompt != "" {
+		req.Messages = append(req.Messages, model.ChatMessage{Role: "user", Content: oreq.Prompt})
+	}
+	applyOllamaOptions(&req, oreq.Options)
+
+	h.serveOllama(ctx, c, req, oreq.Stream == nil || *oreq.Stream, false)
+}
+
+// serveOllama runs a chat or generate request and writes Ollama responses,
+// streamed as newline-delimited JSON when stream is set
+func (h *ChatHandler) serveOllama(ctx context.Context, c *gin.Context, req model.ChatCompletionRequest, stream, chat bool) {
+	start := time.Now()
+
+	respond := func(content string, done bool) model.OllamaResponse {
+		resp := model.OllamaResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339Nano),
+			Done:      done,
+		}
+		if chat {
+			resp.Message = &model.OllamaMessage{Role: "assistant", Content: content}
+		} else {
+			resp.Response = &content
+		}
+		return resp
+	}
+
+	if e := h.routeModel(ctx, c, &req); e != nil {
+		writeOllamaError(c, e.Status, e.Message)
+		return
+	}
+	defer h.recordModel(ctx, c, &req)
+
+	if req.Model == "AkashGen" {
+		writeOllamaError(c, http.StatusBadRequest, "image generation is not supported by the Ollama API")
+		return
+	}
+
+	// Ollama clients send a request without messages to preload a model
+	if len(req.Messages) == 0 {
+		resp := respond("", true)
+		resp.DoneReason = "load"
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	sessionToken, e := h.startRequest(ctx, c, &req)
+	if e != nil {
+		writeOllamaError(c, e.Status, e.Message)
+		return
+	}
+
+	temperature, topP := service.SamplingParams(req)
+
+	// final completes the last response object with timing and token counts
+	var firstToken time.Time
+	final := func(content, finishReason string, usage model.Usage) model.OllamaResponse {
+		end := time.Now()
+		if firstToken.IsZero() {
+			firstToken = end
+		}
+
+		resp := respond(content, true)
+		resp.DoneReason = "stop"
+		if finishReason == service.FinishLength {
+			resp.DoneReason = "length"
+		}
+		resp.TotalDuration = end.Sub(start).Nanoseconds()
+		resp.PromptEvalCount = usage.PromptTokens
+		resp.PromptEvalDuration = firstToken.Sub(start).Nanoseconds()
+		resp.EvalCount = usage.CompletionTokens
+		resp.EvalDuration = end.Sub(firstToken).Nanoseconds()
+		return resp
+	}
+
+	if !stream {
+		var generation *service.Generation
+		err := h.routerService.WithFallback(ctx, &req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+			c.Header(ModelHeader, attempt.Model)
+			generation, err = h.akashService.GenerateText(ctx, attempt, sessionToken, temperature, topP)
+			return err
+		})
+		if err != nil {
+			tracing.RecordError(trace.SpanFromContext(ctx), err)
+			c.Error(err)
+			writeOllamaError(c, http.StatusInternalServerError, "Text generation failed: "+err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, final(generation.Content, generation.FinishReason, generation.Usage))
+		return
+	}
+
+	err := h.routerService.WithFallback(ctx, &req, c.Writer.Written, func(attempt model.ChatCompletionRequest) error {
+		c.Header(ModelHeader, attempt.Model)
+		return h.akashService.StreamTextGeneration(ctx, attempt, sessionToken, temperature, topP, func(event service.StreamEvent) error {
+			switch event.Type {
+			case service.StreamStart:
+				c.Writer.Header().Set("Content-Type", "application/x-ndjson")
+			case service.StreamDelta:
+				if firstToken.IsZero() {
+					firstToken = time.Now()
+				}
+				writeNDJSON(c, respond(event.Content, false))
+			case service.StreamFinish:
+				writeNDJSON(c, final("", event.FinishReason, event.Usage))
+			}
+			return nil
+		})
+	})
+	if err == nil {
+		return
+	}
+
+	tracing.RecordError(trace.SpanFromContext(ctx), err)
+	c.Error(err)
+	if !c.Writer.Written() {
+		writeOllamaError(c, http.StatusInternalServerError, "Text generation failed: "+err.Error())
+		return
+	}
+	writeNDJSON(c, gin.H{"error": err.Error()})
+}
+
+// OllamaTags handles the Ollama-compatible /api/tags endpoint
+func (h *ModelHandler) OllamaTags(c *gin.Context) {
+	models, err := h.catalogService.Models(c.Request.Context())
+	if err != nil {
+		writeOllamaError(c, http.StatusInternalServerError, "Failed to fetch models")
+		return
+	}
+
+	tags := model.OllamaTagsResponse{Models: []model.OllamaModel{}}
+	for _, m := range models {
+		if m.Available && isChatModel(m) {
+			tags.Models = append(tags.Models, toOllamaModel(m.ID, m))
+		}
+	}
+	for _, alias := range h.aliasService.Aliases() {
+		if target, ok := findModel(models, alias.Target); ok && target.Available && isChatModel(target) {
+			tags.Models = append(tags.Models, toOllamaModel(alias.ID, target))
+		}
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// OllamaShow handles the Ollama-compatible /api/show endpoint
+func (h *ModelHandler) OllamaShow(c *gin.Context) {
+	var req model.OllamaShowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeOllamaError(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	name := req.Model
+	if name == "" {
+		name = req.Name
+	}
+	name = ollamaModelName(name)
+
+	m, ok, err := h.catalogService.Lookup(c.Request.Context(), h.aliasService.Target(name))
+	if err != nil {
+		writeOllamaError(c, http.StatusInternalServerError, "Failed to fetch models")
+		return
+	}
+	if !ok {
+		writeOllamaError(c, http.StatusNotFound, "model '"+name+"' not found")
+		return
+	}
+
+	details := ollamaDetails(m)
+	info := map[string]interface{}{
+		"general.architecture": details.Family,
+		"general.basename":     m.Name,
+	}
+	if m.TokenLimit > 0 {
+		info[details.Family+".context_length"] = m.TokenLimit
+	}
+
+	capabilities := []string{"completion"}
+	for _, capability := range service.ModelCapabilities(m) {
+		switch capability {
+		case service.CapabilityVision:
+			capabilities = append(capabilities, "vision")
+		case service.CapabilityReasoning:
+			capabilities = append(capabilities, "thinking")
+		}
+	}
+
+	c.JSON(http.StatusOK, model.OllamaShowResponse{
+		Modelfile:    "FROM " + m.ID + "\n",
+		Parameters:   "temperature 0.6\ntop_p 0.95",
+		Template:     "{{ .Prompt }}",
+		Details:      details,
+		ModelInfo:    info,
+		Capabilities: capabilities,
+		ModifiedAt:   time.Unix(modelCreated, 0).UTC().Format(time.RFC3339),
+	})
+}
+
+// OllamaVersion handles the Ollama-compatible /api/version endpoint
+func (h *ModelHandler) OllamaVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"version": ollamaVersion})
+}
+
+// toOllamaModel describes a catalog model, listed under name, in the Ollama tags format
+func toOllamaModel(name string, m model.Model) model.OllamaModel {
+	sum := sha256.Sum256([]byte(name))
+	return model.OllamaModel{
+		Name:       name + ":latest",
+		Model:      name + ":latest",
+		ModifiedAt: time.Unix(modelCreated, 0).UTC().Format(time.RFC3339),
+		Digest:     hex.EncodeToString(sum[:]),
+		Details:    ollamaDetails(m),
+	}
+}
+
+// ollamaDetails derives Ollama model details from catalog metadata
+func ollamaDetails(m model.Model) model.OllamaModelDetails {
+	family := strings.ToLower(strings.ReplaceAll(m.Architecture, " ", ""))
+	if family == "" {
+		family = "akash"
+	}
+
+	return model.OllamaModelDetails{
+		Family:        family,
+		Families:      []string{family},
+		ParameterSize: m.Parameters,
+	}
+}
+
+// isChatModel reports whether m can serve chat requests
+func isChatModel(m model.Model) bool {
+	for _, capability := range service.ModelCapabilities(m) {
+		if capability == service.CapabilityChat {
+			return true
+		}
+	}
+	return false
+}
+
+// ollamaModelName strips the default Ollama tag from a model name
+func ollamaModelName(name string) string {
+	return strings.TrimSuffix(name, ":latest")
+}
+
+// applyOllamaOptions copies Ollama model options onto req
+func applyOllamaOptions(req *model.ChatCompletionRequest, options model.OllamaOptions) {
+	req.Temperature = options.Temperature
+	req.TopP = options.TopP
+	req.Stop = options.Stop
+	if options.NumPredict != nil && *options.NumPredict > 0 {
+		req.MaxTokens = options.NumPredict
+	}
+}
+
+// writeOllamaError writes an error in the Ollama error format
+func writeOllamaError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{"error": message})
+}
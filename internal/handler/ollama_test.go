@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+func TestOllamaChatStreamsByDefault(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/api/chat",
+		`{"model":"Meta-Llama-3-3-70B-Instruct:latest","messages":[{"role":"user","content":"hi"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+	}
+
+	var responses []model.OllamaResponse
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var resp model.OllamaResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, resp)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("got %d lines, want two deltas and a final object: %s", len(responses), w.Body)
+	}
+
+	var text strings.Builder
+	for _, resp := range responses[:2] {
+		if resp.Done || resp.Message == nil || resp.Message.Role != "assistant" {
+			t.Errorf("delta = %+v, want an unfinished assistant message", resp)
+			continue
+		}
+		text.WriteString(resp.Message.Content)
+	}
+	if text.String() != "Hello world" {
+		t.Errorf("streamed text = %q, want Hello world", text.String())
+	}
+
+	last := responses[2]
+	if !last.Done || last.DoneReason != "stop" || last.EvalCount == 0 || last.TotalDuration == 0 {
+		t.Errorf("final object = %+v, want done with token counts and timings", last)
+	}
+	if last.Model != "Meta-Llama-3-3-70B-Instruct" {
+		t.Errorf("model = %q, want the name without :latest", last.Model)
+	}
+}
+
+func TestOllamaGenerate(t *testing.T) {
+	r, upstream := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/api/generate",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","system":"Be brief.","prompt":"hi","stream":false}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var resp model.OllamaResponse
+	decode(t, w, &resp)
+	if resp.Response == nil || *resp.Response != "Hello world" || resp.Message != nil {
+		t.Errorf("response = %+v, want Hello world in response", resp)
+	}
+	if !resp.Done || resp.PromptEvalCount == 0 {
+		t.Errorf("response = %+v, want done with a prompt token count", resp)
+	}
+
+	requests := upstream.chatRequests()
+	if len(requests) != 1 || len(requests[0].Messages) != 2 || requests[0].Messages[0].Role != "system" {
+		t.Errorf("upstream requests = %+v, want the system prompt before the prompt", requests)
+	}
+}
+
+func TestOllamaGenerateWithoutPromptLoadsModel(t *testing.T) {
+	r, upstream := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/api/generate", `{"model":"Meta-Llama-3-3-70B-Instruct"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var resp model.OllamaResponse
+	decode(t, w, &resp)
+	if !resp.Done || resp.DoneReason != "load" {
+		t.Errorf("response = %+v, want done_reason load", resp)
+	}
+	if n := len(upstream.chatRequests()); n != 0 {
+		t.Errorf("upstream received %d chat requests, want none", n)
+	}
+}
+
+func TestOllamaChatUnknownModel(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/api/chat",
+		`{"model":"llama3","messages":[{"role":"user","content":"hi"}]}`)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var resp struct {
+		Error string `json:"error"`
+	}
+	decode(t, w, &resp)
+	if resp.Error == "" {
+		t.Errorf("body %s does not carry an Ollama error", w.Body)
+	}
+}
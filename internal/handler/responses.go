@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/auth"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/006lp/akashchat-api-go/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Responses handles the OpenAI-compatible /v1/responses endpoint
+func (h *ChatHandler) Responses(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ChatHandler.Responses")
+	defer span.End()
+
+	var rreq model.ResponsesRequest
+	if err := c.ShouldBindJSON(&rreq); err != nil {
+		writeAPIError(c, &chatError{Status: http.StatusBadRequest, Message: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	// Continue the conversation of the previous response
+	var history []model.ChatMessage
+	if rreq.PreviousResponseID != "" {
+		previous, ok := h.responseStore.Get(rreq.PreviousResponseID, auth.KeyName(c))
+		if !ok {
+			writeAPIError(c, &chatError{
+				Status:  http.StatusNotFound,
+				Type:    "previous_response_not_found",
+				Message: fmt.Sprintf("Previous response with id '%s' not found.", rreq.PreviousResponseID),
+			})
+			return
+		}
+		history = append(history, previous.Messages...)
+	}
+
+	input, err := fromResponseInput(rreq.Input)
+	if err != nil {
+		writeAPIError(c, &chatError{Status: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	history = append(history, input...)
+
+	req := model.ChatCompletionRequest{
+		Model:       rreq.Model,
+		Temperature: rreq.Temperature,
+		TopP:        rreq.TopP,
+		MaxTokens:   rreq.MaxOutputTokens,
+	}
+	if rreq.Instructions != "" {
+		req.Messages = append(req.Messages, model.ChatMessage{Role: "system", Content: rreq.Instructions})
+	}
+	req.Messages = append(req.Messages, history...)
+
+	if e := h.routeModel(ctx, c, &req); e != nil {
+		writeAPIError(c, e)
+		return
+	}
+	defer h.recordModel(ctx, c, &req)
+
+	if req.Model == "AkashGen" {
+		writeAPIError(c, &chatError{Status: http.StatusBadRequest, Message: "Image generation is not supported by the Responses API"})
+		return
+	}
+
+	sessionToken, e := h.startRequest(ctx, c, &req)
+	if e != nil {
+		writeAPIError(c, e)
+		return
+	}
+
+	temperature, topP := service.SamplingParams(req)
+	resp := newResponse(rreq, req.Model)
+
+	if rreq.Stream {
+		h.streamResponse(ctx, c, req, sessionToken, temperature, topP, resp, history)
+		return
+	}
+
+	var generation *service.Generation
+	err = h.routerService.WithFallback(ctx, &req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+		c.Header(ModelHeader, attempt.Model)
+		generation, err = h.akashService.GenerateText(ctx, attempt, sessionToken, temperature, topP)
+		return err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		c.Error(err)
+		writeAPIError(c, &chatError{Status: http.StatusInternalServerError, Message: "Text generation failed: " + err.Error()})
+		return
+	}
+
+	resp.Model = req.Model
+	completeResponse(resp, "msg_"+utils.GenerateRandomID(24), generation.Content, generation.FinishReason, generation.Usage)
+	h.storeResponse(c, resp, history)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// streamResponse streams a generation as Responses API semantic events
+func (h *ChatHandler) streamResponse(ctx context.Context, c *gin.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64, resp *model.Response, history []model.ChatMessage) {
+	index := 0
+	sequence := 0
+	itemID := "msg_" + utils.GenerateRandomID(24)
+	var text string
+
+	send := func(event model.ResponseStreamEvent) {
+		event.SequenceNumber = sequence
+		sequence++
+		writeSSE(c, event.Type, event)
+	}
+
+	// snapshot copies resp so later changes do not alter events already built
+	snapshot := func() *model.Response {
+		copied := *resp
+		return &copied
+	}
+
+	err := h.routerService.WithFallback(ctx, &req, c.Writer.Written, func(attempt model.ChatCompletionRequest) error {
+		c.Header(ModelHeader, attempt.Model)
+		return h.akashService.StreamTextGeneration(ctx, attempt, sessionToken, temperature, topP, func(event service.StreamEvent) error {
+			switch event.Type {
+			case service.StreamStart:
+				startSSE(c)
+				resp.Model = attempt.Model
+				send(model.ResponseStreamEvent{Type: "response.created", Response: snapshot()})
+				send(model.ResponseStreamEvent{Type: "response.in_progress", Response: snapshot()})
+				send(model.ResponseStreamEvent{
+					Type:        "response.output_item.added",
+					OutputIndex: &index,
+					Item: &model.ResponseOutputItem{
+						Type:    "message",
+						ID:      itemID,
+						Status:  "in_progress",
+						Role:    "assistant",
+						Content: []model.ResponseOutputText{},
+					},
+				})
+				send(model.ResponseStreamEvent{
+					Type:         "response.content_part.added",
+					ItemID:       itemID,
+					OutputIndex:  &index,
+					ContentIndex: &index,
+					Part:         outputText(""),
+				})
+			case service.StreamDelta:
+				text += event.Content
+				send(model.ResponseStreamEvent{
+					Type:         "response.output_text.delta",
+					ItemID:       itemID,
+					OutputIndex:  &index,
+					ContentIndex: &index,
+					Delta:        event.Content,
+				})
+			case service.StreamFinish:
+				completeResponse(resp, itemID, text, event.FinishReason, event.Usage)
+				item := resp.Output[0]
+
+				send(model.ResponseStreamEvent{
+					Type:         "response.output_text.done",
+					ItemID:       itemID,
+					OutputIndex:  &index,
+					ContentIndex: &index,
+					Text:         &text,
+				})
+				send(model.ResponseStreamEvent{
+					Type:         "response.content_part.done",
+					ItemID:       itemID,
+					OutputIndex:  &index,
+					ContentIndex: &index,
+					Part:         &item.Content[0],
+				})
+				send(model.ResponseStreamEvent{Type: "response.output_item.done", OutputIndex: &index, Item: &item})
+
+				eventType := "response.completed"
+				if resp.Status == "incomplete" {
+					eventType = "response.incomplete"
+				}
+				send(model.ResponseStreamEvent{Type: eventType, Response: resp})
+				h.storeResponse(c, resp, history)
+			}
+			return nil
+		})
+	})
+	if err == nil {
+		return
+	}
+
+	tracing.RecordError(trace.SpanFromContext(ctx), err)
+	c.Error(err)
+	if !c.Writer.Written() {
+		writeAPIError(c, &chatError{Status: http.StatusInternalServerError, Message: "Text generation failed: " + err.Error()})
+		return
+	}
+
+	resp.Status = "failed"
+	resp.Error = &model.ResponseError{Code: "server_error", Message: err.Error()}
+	send(model.ResponseStreamEvent{Type: "response.failed", Response: resp})
+}
+
+// GetResponse handles the /v1/responses/:id endpoint
+func (h *ChatHandler) GetResponse(c *gin.Context) {
+	stored, ok := h.responseStore.Get(c.Param("id"), auth.KeyName(c))
+	if !ok {
+		writeAPIError(c, &chatError{Status: http.StatusNotFound, Message: "Response not found."})
+		return
+	}
+
+	c.JSON(http.StatusOK, stored.Response)
+}
+
+// DeleteResponse handles the DELETE /v1/responses/:id endpoint
+func (h *ChatHandler) DeleteResponse(c *gin.Context) {
+	id := c.Param("id")
+	if !h.responseStore.Delete(id, auth.KeyName(c)) {
+		writeAPIError(c, &chatError{Status: http.StatusNotFound, Message: "Response not found."})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.ResponseDeleted{ID: id, Object: "response.deleted", Deleted: true})
+}
+
+// storeResponse keeps resp and its conversation for chaining unless the client
+// opted out. Only the API key that made the request can read it back.
+func (h *ChatHandler) storeResponse(c *gin.Context, resp *model.Response, history []model.ChatMessage) {
+	if !resp.Store {
+		return
+	}
+
+	messages := append([]model.ChatMessage(nil), history...)
+	messages = append(messages, model.ChatMessage{Role: "assistant", Content: resp.Output[0].Content[0].Text})
+	h.responseStore.Put(&service.StoredResponse{Response: resp, Messages: messages, Owner: auth.KeyName(c)})
+}
+
+// fromResponseInput converts input items to chat messages. The developer role is
+// treated as system.
+func fromResponseInput(input model.ResponseInput) ([]model.ChatMessage, error) {
+	var messages []model.ChatMessage
+	for _, item := range input {
+		if item.Type != "" && item.Type != "message" {
+			return nil, fmt.Errorf("input item type %q is not supported", item.Type)
+		}
+
+		role := item.Role
+		switch role {
+		case "user", "assistant", "system":
+		case "developer":
+			role = "system"
+		default:
+			return nil, fmt.Errorf("invalid input role %q", item.Role)
+		}
+
+		var text string
+		for _, part := range item.Content {
+			if part.Type != "input_text" && part.Type != "output_text" {
+				return nil, fmt.Errorf("content type %q is not supported", part.Type)
+			}
+			text += part.Text
+		}
+		messages = append(messages, model.ChatMessage{Role: role, Content: text})
+	}
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("input must not be empty")
+	}
+	return messages, nil
+}
+
+// newResponse creates an in-progress response for rreq
+func newResponse(rreq model.ResponsesRequest, modelID string) *model.Response {
+	resp := &model.Response{
+		ID:              "resp_" + utils.GenerateRandomID(24),
+		Object:          "response",
+		CreatedAt:       time.Now().Unix(),
+		Status:          "in_progress",
+		Model:           modelID,
+		Output:          []model.ResponseOutputItem{},
+		Temperature:     rreq.Temperature,
+		TopP:            rreq.TopP,
+		MaxOutputTokens: rreq.MaxOutputTokens,
+		Store:           rreq.Store == nil || *rreq.Store,
+		Metadata:        rreq.Metadata,
+	}
+	if rreq.Instructions != "" {
+		resp.Instructions = &rreq.Instructions
+	}
+	if rreq.PreviousResponseID != "" {
+		resp.PreviousResponseID = &rreq.PreviousResponseID
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = map[string]string{}
+	}
+	return resp
+}
+
+// completeResponse sets the output message, status and usage of resp
+func completeResponse(resp *model.Response, itemID, text, finishReason string, usage model.Usage) {
+	resp.Status = "completed"
+	if finishReason == service.FinishLength {
+		resp.Status = "incomplete"
+		resp.IncompleteDetails = &model.ResponseIncompleteDetails{Reason: "max_output_tokens"}
+	}
+
+	resp.Output = []model.ResponseOutputItem{{
+		Type:    "message",
+		ID:      itemID,
+		Status:  resp.Status,
+		Role:    "assistant",
+		Content: []model.ResponseOutputText{*outputText(text)},
+	}}
+	resp.Usage = &model.ResponseUsage{
+		InputTokens:  usage.PromptTokens,
+		OutputTokens: usage.CompletionTokens,
+		TotalTokens:  usage.TotalTokens,
+	}
+}
+
+// outputText returns an output_text content part
+func outputText(text string) *model.ResponseOutputText {
+	return &model.ResponseOutputText{Type: "output_text", Text: text, Annotations: []interface{}{}}
+}
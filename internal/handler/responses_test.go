@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/auth"
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+func TestResponsesChainsPreviousResponse(t *testing.T) {
+	r, upstream := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1/responses",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","instructions":"Be brief.","input":"hi"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var first model.Response
+	decode(t, w, &first)
+	if first.Object != "response" || first.Status != "completed" || !strings.HasPrefix(first.ID, "resp_") {
+		t.Errorf("object = %q, status = %q, id = %q", first.Object, first.Status, first.ID)
+	}
+	if len(first.Output) != 1 || len(first.Output[0].Content) != 1 || first.Output[0].Content[0].Text != "Hello world" {
+		t.Fatalf("output = %+v, want one message of Hello world", first.Output)
+	}
+	if first.Usage == nil || first.Usage.OutputTokens == 0 {
+		t.Errorf("usage = %+v, want token counts", first.Usage)
+	}
+
+	// The follow-up carries the stored conversation but not the old instructions
+	w = serve(r, http.MethodPost, "/v1/responses",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","previous_response_id":"`+first.ID+`","input":[{"role":"user","content":"again"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	var second model.Response
+	decode(t, w, &second)
+	if second.PreviousResponseID == nil || *second.PreviousResponseID != first.ID {
+		t.Errorf("previous_response_id = %v, want %s", second.PreviousResponseID, first.ID)
+	}
+
+	requests := upstream.chatRequests()
+	if len(requests) != 2 {
+		t.Fatalf("upstream received %d requests, want 2", len(requests))
+	}
+	var turns []string
+	for _, msg := range requests[1].Messages {
+		turns = append(turns, msg.Role+":"+msg.Content)
+	}
+	if got := strings.Join(turns, "|"); got != "user:hi|assistant:Hello world|user:again" {
+		t.Errorf("follow-up messages = %s", got)
+	}
+}
+
+func TestResponsesStoreLifecycle(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1/responses", `{"model":"Meta-Llama-3-3-70B-Instruct","input":"hi"}`)
+	var resp model.Response
+	decode(t, w, &resp)
+
+	if w := serve(r, http.MethodGet, "/v1/responses/"+resp.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("GET status = %d, want 200", w.Code)
+	}
+	if w := serve(r, http.MethodDelete, "/v1/responses/"+resp.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("DELETE status = %d, want 200", w.Code)
+	}
+	if w := serve(r, http.MethodGet, "/v1/responses/"+resp.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET after DELETE status = %d, want 404", w.Code)
+	}
+
+	// Responses created with store set to false cannot be fetched or continued
+	w = serve(r, http.MethodPost, "/v1/responses", `{"model":"Meta-Llama-3-3-70B-Instruct","input":"hi","store":false}`)
+	decode(t, w, &resp)
+	if resp.Store {
+		t.Error("store = true, want false")
+	}
+	w = serve(r, http.MethodPost, "/v1/responses",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","previous_response_id":"`+resp.ID+`","input":"again"}`)
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "previous_response_not_found") {
+		t.Errorf("status = %d, body = %s, want previous_response_not_found", w.Code, w.Body)
+	}
+}
+
+func TestResponsesScopedToAPIKey(t *testing.T) {
+	keys, err := auth.ParseKeys("alice:key-a,bob:key-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := newTestRouter(t, nil, auth.Middleware(keys))
+
+	send := func(key, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+key)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("key-a", http.MethodPost, "/v1/responses", `{"model":"Meta-Llama-3-3-70B-Instruct","input":"hi"}`)
+	var resp model.Response
+	decode(t, w, &resp)
+
+	// Another key can neither read, continue nor delete the response
+	if w := send("key-b", http.MethodGet, "/v1/responses/"+resp.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET with another key: status = %d, want 404", w.Code)
+	}
+	w = send("key-b", http.MethodPost, "/v1/responses",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","previous_response_id":"`+resp.ID+`","input":"again"}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("chaining with another key: status = %d, want 404", w.Code)
+	}
+	if w := send("key-b", http.MethodDelete, "/v1/responses/"+resp.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("DELETE with another key: status = %d, want 404", w.Code)
+	}
+
+	if w := send("key-a", http.MethodGet, "/v1/responses/"+resp.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("GET with the creating key: status = %d, want 200", w.Code)
+	}
+	if w := send("key-a", http.MethodDelete, "/v1/responses/"+resp.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("DELETE with the creating key: status = %d, want 200", w.Code)
+	}
+}
+
+func TestResponsesStream(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	w := serve(r, http.MethodPost, "/v1/responses",
+		`{"model":"Meta-Llama-3-3-70B-Instruct","input":"hi","stream":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var types []string
+	var text strings.Builder
+	var last model.ResponseStreamEvent
+	for i, data := range sseData(t, w.Body.String()) {
+		if err := json.Unmarshal([]byte(data), &last); err != nil {
+			t.Fatalf("invalid event %q: %v", data, err)
+		}
+		if last.SequenceNumber != i {
+			t.Errorf("event %d has sequence_number %d", i, last.SequenceNumber)
+		}
+		types = append(types, last.Type)
+		if last.Type == "response.output_text.delta" {
+			text.WriteString(last.Delta)
+		}
+	}
+
+	want := strings.Join([]string{
+		"response.created", "response.in_progress", "response.output_item.added", "response.content_part.added",
+		"response.output_text.delta", "response.output_text.delta", "response.output_text.done",
+		"response.content_part.done", "response.output_item.done", "response.completed",
+	}, ",")
+	if got := strings.Join(types, ","); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+	if text.String() != "Hello world" {
+		t.Errorf("streamed text = %q, want Hello world", text.String())
+	}
+	if last.Response == nil || last.Response.Status != "completed" || last.Response.Output[0].Content[0].Text != "Hello world" {
+		t.Errorf("completed response = %+v", last.Response)
+	}
+
+	// Streamed responses are stored like any other
+	if w := serve(r, http.MethodGet, "/v1/responses/"+last.Response.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("GET status = %d, want 200", w.Code)
+	}
+}
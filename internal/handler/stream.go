@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// startSSE sets the headers for a server-sent event stream
+func startSSE(c *gin.Context) {
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+}
+
+// writeSSE writes a named server-sent event with a JSON payload and flushes it
+func writeSSE(c *gin.Context, event string, data interface{}) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(data)
+
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n", event, buf.String())
+	c.Writer.Flush()
+}
+
+// writeNDJSON writes v as a single line of newline-delimited JSON and flushes it
+func writeNDJSON(c *gin.Context, v interface{}) {
+	enc := json.NewEncoder(c.Writer)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	c.Writer.Flush()
+}
+
+// writeSSEData writes an unnamed server-sent event with a JSON payload and flushes it
+func writeSSEData(c *gin.Context, data interface{}) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(data)
+
+	fmt.Fprintf(c.Writer, "data: %s\n", buf.String())
+	c.Writer.Flush()
+}
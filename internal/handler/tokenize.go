@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tokenizer"
+	"github.com/gin-gonic/gin"
+)
+
+// TokenizeHandler handles token counting requests
+type TokenizeHandler struct {
+	catalogService *service.CatalogService
+	aliasService   *service.AliasService
+}
+
+// NewTokenizeHandler creates a new TokenizeHandler instance
+func NewTokenizeHandler(catalogService *service.CatalogService, aliasService *service.AliasService) *TokenizeHandler {
+	return &TokenizeHandler{
+		catalogService: catalogService,
+		aliasService:   aliasService,
+	}
+}
+
+// Tokenize handles the /v1/tokenize endpoint. Input text is counted with the
+// encoding used for the model and returned with its token IDs when that encoding
+// is the model's own; otherwise the count is marked as an estimate. Messages are
+// counted as the prompt sent upstream, including the system prompt, the same way
+// as usage.prompt_tokens.
+func (h *TokenizeHandler) Tokenize(c *gin.Context) {
+	var req model.TokenizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Code: 400,
+			Data: model.ErrorData{Message: "Invalid request format: " + err.Error()},
+		})
+		return
+	}
+
+	if (req.Input == "") == (len(req.Messages) == 0) {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Code: 400,
+			Data: model.ErrorData{Message: "Exactly one of input or messages is required"},
+		})
+		return
+	}
+
+	chatReq := model.ChatCompletionRequest{Model: req.Model, Messages: req.Messages}
+	h.aliasService.Resolve(&chatReq)
+
+	m, ok, err := h.catalogService.Lookup(c.Request.Context(), chatReq.Model)
+	if err != nil || !ok {
+		m = model.Model{ID: chatReq.Model}
+	}
+	tok := tokenizer.ForModel(m)
+
+	resp := model.TokenizeResponse{
+		Model:      chatReq.Model,
+		Tokenizer:  tok.Name(),
+		Estimated:  !tok.Exact(),
+		TokenLimit: m.TokenLimit,
+	}
+	if req.Input != "" {
+		resp.Tokens = tok.Encode(req.Input)
+		resp.Count = tok.Count(req.Input)
+	} else {
+		resp.Count = service.CountRequestTokens(tok, chatReq)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
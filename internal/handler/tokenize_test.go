@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/tokenizer"
+)
+
+func TestTokenize(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+
+	tests := []struct {
+		model     string
+		tokenizer string
+		estimated bool
+		tokens    []int
+	}{
+		{"gpt-oss-120b", tokenizer.O200K, false, []int{13225, 11, 2375, 0}},
+		{"Meta-Llama-3-3-70B-Instruct", tokenizer.CL100K, true, nil},
+		{"unknown-model", tokenizer.Characters, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			w := serve(r, http.MethodPost, "/v1/tokenize", `{"model":"`+tt.model+`","input":"Hello, world!"}`)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+
+			var resp model.TokenizeResponse
+			decode(t, w, &resp)
+			if resp.Tokenizer != tt.tokenizer || resp.Estimated != tt.estimated || resp.Count != 4 {
+				t.Errorf("response = %+v", resp)
+			}
+			if !slices.Equal(resp.Tokens, tt.tokens) {
+				t.Errorf("tokens = %v, want %v", resp.Tokens, tt.tokens)
+			}
+		})
+	}
+}
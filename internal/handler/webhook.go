@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles webhook delivery inspection requests
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// GetDelivery handles the /v1/webhooks/deliveries/:id endpoint
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	delivery, ok := h.webhookService.GetDelivery(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Code: 404,
+			Data: model.ErrorData{Message: "Delivery not found."},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Code: 200,
+		Data: delivery,
+	})
+}
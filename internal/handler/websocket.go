@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/auth"
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket frame types sent by clients
+const (
+	wsFrameChat   = "chat"
+	wsFrameCancel = "cancel"
+	wsFramePing   = "ping"
+)
+
+// WebSocket frame types sent by the server
+const (
+	wsFrameStart     = "start"
+	wsFrameDelta     = "delta"
+	wsFrameDone      = "done"
+	wsFrameCancelled = "cancelled"
+	wsFrameError     = "error"
+	wsFramePong      = "pong"
+)
+
+// WebSocket connection limits
+const (
+	wsMaxGenerations = 8
+	wsMaxFrameSize   = 4 << 20
+	wsWriteTimeout   = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingInterval   = 30 * time.Second
+)
+
+// wsUpgrader upgrades chat connections. Origins are checked against the
+// configured allowlist by auth.WebSocketMiddleware before the handler runs.
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	Subprotocols:    []string{auth.WebSocketProtocol},
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// wsConn serializes writes to a WebSocket connection and tracks its running generations
+type wsConn struct {
+	conn       *websocket.Conn
+	writeMutex sync.Mutex
+	mutex      sync.Mutex
+	running    map[string]*wsGeneration
+	wg         sync.WaitGroup
+}
+
+// wsGeneration is a generation running on a connection
+type wsGeneration struct {
+	id     string
+	cancel context.CancelFunc
+}
+
+// send writes a frame to the client
+func (w *wsConn) send(frame model.WSServerFrame) error {
+	w.writeMutex.Lock()
+	defer w.writeMutex.Unlock()
+
+	w.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return w.conn.WriteJSON(frame)
+}
+
+// sendError writes an error frame for the generation id
+func (w *wsConn) sendError(id string, e *chatError) error {
+	return w.send(wsErrorFrame(id, e))
+}
+
+// end unregisters g before writing its final frame, so that the client can reuse
+// the ID and the slot as soon as it sees the frame
+func (w *wsConn) end(g *wsGeneration, frame model.WSServerFrame) error {
+	w.finish(g)
+	return w.send(frame)
+}
+
+// wsErrorFrame returns an error frame for the generation id
+func wsErrorFrame(id string, e *chatError) model.WSServerFrame {
+	return model.WSServerFrame{
+		Type: wsFrameError,
+		ID:   id,
+		Error: &model.ErrorData{
+			Message:     e.Message,
+			Type:        e.Type,
+			Suggestions: e.Suggestions,
+		},
+	}
+}
+
+// start registers a generation, failing when the ID is in use or the connection
+// is at its limit
+func (w *wsConn) start(id string, cancel context.CancelFunc) (*wsGeneration, *chatError) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, ok := w.running[id]; ok {
+		return nil, &chatError{Status: http.StatusConflict, Message: "A generation with this id is already running"}
+	}
+	if len(w.running) >= wsMaxGenerations {
+		return nil, &chatError{Status: http.StatusTooManyRequests, Message: "Too many concurrent generations on this connection"}
+	}
+	g := &wsGeneration{id: id, cancel: cancel}
+	w.running[id] = g
+	w.wg.Add(1)
+	return g, nil
+}
+
+// finish unregisters g. It does nothing when g has already been unregistered,
+// even if a new generation has since taken its ID.
+func (w *wsConn) finish(g *wsGeneration) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.running[g.id] == g {
+		delete(w.running, g.id)
+	}
+}
+
+// cancel aborts a running generation, reporting whether it was found
+func (w *wsConn) cancel(id string) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	g, ok := w.running[id]
+	if ok {
+		g.cancel()
+	}
+	return ok
+}
+
+// WebSocketChat handles the /v1/ws/chat endpoint. Clients send chat requests as
+// JSON frames tagged with an ID and receive the streamed generation as frames
+// carrying the same ID, so several generations can run on one connection.
+func (h *ChatHandler) WebSocketChat(c *gin.Context) {
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written an error response
+		c.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	ws := &wsConn{conn: conn, running: make(map[string]*wsGeneration)}
+	defer func() {
+		cancel()
+		ws.wg.Wait()
+		conn.Close()
+	}()
+
+	conn.SetReadLimit(wsMaxFrameSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	go h.keepAlive(ctx, ws)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logging.FromContext(ctx).Warn("websocket connection closed", "error", err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		var frame model.WSClientFrame
+		if err := json.Unmarshal(data, &frame); err != nil {
+			ws.sendError("", &chatError{Status: http.StatusBadRequest, Message: "Invalid frame: " + err.Error()})
+			continue
+		}
+
+		switch frame.Type {
+		case wsFrameChat:
+			h.startWSGeneration(ctx, ws, frame)
+		case wsFrameCancel:
+			if !ws.cancel(frame.ID) {
+				ws.sendError(frame.ID, &chatError{Status: http.StatusNotFound, Message: "No running generation with this id"})
+			}
+		case wsFramePing:
+			ws.send(model.WSServerFrame{Type: wsFramePong, ID: frame.ID})
+		default:
+			ws.sendError(frame.ID, &chatError{Status: http.StatusBadRequest, Message: "Unknown frame type: " + frame.Type})
+		}
+	}
+}
+
+// keepAlive pings the client until ctx is done so dead connections are detected
+func (h *ChatHandler) keepAlive(ctx context.Context, ws *wsConn) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ws.writeMutex.Lock()
+			err := ws.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+			ws.writeMutex.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// startWSGeneration validates a chat frame and runs its generation in the background
+func (h *ChatHandler) startWSGeneration(ctx context.Context, ws *wsConn, frame model.WSClientFrame) {
+	if frame.ID == "" {
+		ws.sendError("", &chatError{Status: http.StatusBadRequest, Message: "Chat frames require an id"})
+		return
+	}
+	if frame.Request == nil {
+		ws.sendError(frame.ID, &chatError{Status: http.StatusBadRequest, Message: "Chat frames require a request"})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(frame.Request); err != nil {
+		ws.sendError(frame.ID, &chatError{Status: http.StatusBadRequest, Message: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	g, e := ws.start(frame.ID, cancel)
+	if e != nil {
+		cancel()
+		ws.sendError(frame.ID, e)
+		return
+	}
+
+	go func() {
+		defer ws.wg.Done()
+		defer cancel()
+		defer ws.finish(g)
+		h.runWSGeneration(ctx, ws, g, *frame.Request)
+	}()
+}
+
+// runWSGeneration streams the generation g to the client as frames tagged with its ID
+func (h *ChatHandler) runWSGeneration(ctx context.Context, ws *wsConn, g *wsGeneration, req model.ChatCompletionRequest) {
+	ctx, span := tracing.Start(ctx, "ChatHandler.runWSGeneration")
+	defer span.End()
+
+	id := g.id
+
+	// fail reports e, or the cancellation that caused it
+	fail := func(e *chatError) {
+		if ctx.Err() != nil {
+			ws.end(g, model.WSServerFrame{Type: wsFrameCancelled, ID: id})
+			return
+		}
+		ws.end(g, wsErrorFrame(id, e))
+	}
+
+	if e := h.resolveModel(ctx, &req); e != nil {
+		fail(e)
+		return
+	}
+
+	if req.Model == "AkashGen" {
+		fail(&chatError{Status: http.StatusBadRequest, Message: "Image generation is not supported over WebSocket"})
+		return
+	}
+
+	sessionToken, _, e := h.prepareRequest(ctx, &req)
+	if e != nil {
+		fail(e)
+		return
+	}
+
+	temperature, topP := service.SamplingParams(req)
+
+	started := false
+	err := h.routerService.WithFallback(ctx, &req, func() bool { return started }, func(attempt model.ChatCompletionRequest) error {
+		return h.akashService.StreamTextGeneration(ctx, attempt, sessionToken, temperature, topP, func(event service.StreamEvent) error {
+			switch event.Type {
+			case service.StreamStart:
+				started = true
+				return ws.send(model.WSServerFrame{Type: wsFrameStart, ID: id, Model: attempt.Model, MessageID: event.MessageID})
+			case service.StreamDelta:
+				return ws.send(model.WSServerFrame{Type: wsFrameDelta, ID: id, Content: event.Content})
+			case service.StreamFinish:
+				usage := event.Usage
+				return ws.end(g, model.WSServerFrame{Type: wsFrameDone, ID: id, FinishReason: event.FinishReason, Usage: &usage})
+			}
+			return nil
+		})
+	})
+	if err == nil {
+		return
+	}
+
+	if ctx.Err() == nil {
+		tracing.RecordError(span, err)
+		logging.FromContext(ctx).Warn("websocket generation failed", "id", id, "model", req.Model, "error", err)
+	}
+	fail(&chatError{Status: http.StatusInternalServerError, Message: "Text generation failed: " + err.Error()})
+}
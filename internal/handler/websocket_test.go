@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/auth"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// dialChat starts r on a test server and opens a chat WebSocket to it
+func dialChat(t *testing.T, r *gin.Engine, dialer *websocket.Dialer, query string) (*websocket.Conn, *http.Response) {
+	t.Helper()
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/ws/chat" + query
+	conn, resp, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, resp
+}
+
+// sendFrame writes a client frame
+func sendFrame(t *testing.T, conn *websocket.Conn, frame model.WSClientFrame) {
+	t.Helper()
+	if err := conn.WriteJSON(frame); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+}
+
+// readFrame reads the next server frame, failing when none arrives in time
+func readFrame(t *testing.T, conn *websocket.Conn) model.WSServerFrame {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var frame model.WSServerFrame
+	if err := conn.ReadJSON(&frame); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return frame
+}
+
+// chatFrame returns a chat frame for a single user message to modelID
+func chatFrame(id, modelID string) model.WSClientFrame {
+	return model.WSClientFrame{
+		Type: wsFrameChat,
+		ID:   id,
+		Request: &model.ChatCompletionRequest{
+			Model:    modelID,
+			Messages: []model.ChatMessage{{Role: "user", Content: "hi"}},
+		},
+	}
+}
+
+func TestWebSocketChatMultiplexing(t *testing.T) {
+	keys, _ := auth.ParseKeys("ci:secret")
+	r, _ := newTestRouter(t, nil, auth.WebSocketMiddleware(keys, ""))
+	dialer := &websocket.Dialer{Subprotocols: []string{auth.WebSocketProtocol, "bearer.secret"}}
+	conn, resp := dialChat(t, r, dialer, "")
+
+	if got := resp.Header.Get("Sec-WebSocket-Protocol"); got != auth.WebSocketProtocol {
+		t.Errorf("Sec-WebSocket-Protocol = %q, want %q", got, auth.WebSocketProtocol)
+	}
+
+	sendFrame(t, conn, chatFrame("a", "Meta-Llama-3-3-70B-Instruct"))
+	sendFrame(t, conn, chatFrame("b", "Qwen3-235B-A22B-Instruct-2507-FP8"))
+	sendFrame(t, conn, model.WSClientFrame{Type: wsFramePing, ID: "p"})
+
+	content := map[string]string{}
+	done := map[string]model.WSServerFrame{}
+	started := map[string]string{}
+	pong := false
+	for len(done) < 2 || !pong {
+		frame := readFrame(t, conn)
+		switch frame.Type {
+		case wsFrameStart:
+			started[frame.ID] = frame.Model
+		case wsFrameDelta:
+			content[frame.ID] += frame.Content
+		case wsFrameDone:
+			done[frame.ID] = frame
+		case wsFramePong:
+			pong = frame.ID == "p"
+		default:
+			t.Fatalf("unexpected frame %+v", frame)
+		}
+	}
+
+	if started["a"] != "Meta-Llama-3-3-70B-Instruct" || started["b"] != "Qwen3-235B-A22B-Instruct-2507-FP8" {
+		t.Errorf("start frames = %v", started)
+	}
+	for _, id := range []string{"a", "b"} {
+		if content[id] != "Hello world" {
+			t.Errorf("content of %s = %q, want %q", id, content[id], "Hello world")
+		}
+		if done[id].FinishReason != "stop" || done[id].Usage == nil {
+			t.Errorf("done frame of %s = %+v", id, done[id])
+		}
+	}
+}
+
+func TestWebSocketChatCancel(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+	conn, _ := dialChat(t, r, websocket.DefaultDialer, "")
+
+	sendFrame(t, conn, chatFrame("slow", "Slow-Model"))
+	if frame := readFrame(t, conn); frame.Type != wsFrameStart || frame.ID != "slow" {
+		t.Fatalf("first frame = %+v, want start of slow", frame)
+	}
+
+	sendFrame(t, conn, model.WSClientFrame{Type: wsFrameCancel, ID: "slow"})
+	if frame := readFrame(t, conn); frame.Type != wsFrameCancelled || frame.ID != "slow" {
+		t.Fatalf("frame after cancel = %+v, want cancelled", frame)
+	}
+
+	// The generation is gone once cancelled
+	sendFrame(t, conn, model.WSClientFrame{Type: wsFrameCancel, ID: "slow"})
+	if frame := readFrame(t, conn); frame.Type != wsFrameError || frame.ID != "slow" {
+		t.Fatalf("frame after second cancel = %+v, want error", frame)
+	}
+}
+
+func TestWebSocketChatGenerationLimit(t *testing.T) {
+	r, _ := newTestRouter(t, nil)
+	conn, _ := dialChat(t, r, websocket.DefaultDialer, "")
+
+	for i := range wsMaxGenerations {
+		sendFrame(t, conn, chatFrame(strconv.Itoa(i), "Slow-Model"))
+	}
+	for range wsMaxGenerations {
+		if frame := readFrame(t, conn); frame.Type != wsFrameStart {
+			t.Fatalf("frame = %+v, want start", frame)
+		}
+	}
+
+	sendFrame(t, conn, chatFrame("0", "Slow-Model"))
+	if frame := readFrame(t, conn); frame.Type != wsFrameError || frame.ID != "0" || !strings.Contains(frame.Error.Message, "already running") {
+		t.Errorf("frame for a duplicate id = %+v, want an error", frame)
+	}
+
+	sendFrame(t, conn, chatFrame("extra", "Slow-Model"))
+	frame := readFrame(t, conn)
+	if frame.Type != wsFrameError || frame.ID != "extra" || !strings.Contains(frame.Error.Message, "Too many concurrent generations") {
+		t.Fatalf("frame over the limit = %+v, want an error", frame)
+	}
+
+	// Cancelling a generation frees a slot
+	sendFrame(t, conn, model.WSClientFrame{Type: wsFrameCancel, ID: "0"})
+	if frame := readFrame(t, conn); frame.Type != wsFrameCancelled || frame.ID != "0" {
+		t.Fatalf("frame after cancel = %+v, want cancelled", frame)
+	}
+	sendFrame(t, conn, chatFrame("extra", "Slow-Model"))
+	if frame := readFrame(t, conn); frame.Type != wsFrameStart || frame.ID != "extra" {
+		t.Errorf("frame after freeing a slot = %+v, want start", frame)
+	}
+}
@@ -0,0 +1,218 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header used to accept and return request IDs
+const RequestIDHeader = "X-Request-ID"
+
+// Request log field names set by handlers and services
+const (
+	FieldModel             = "model"
+	FieldModelAlias        = "model_alias"
+	FieldKeyName           = "key_name"
+	FieldUpstreamMessageID = "upstream_message_id"
+	FieldPrompt            = "prompt"
+	FieldContextAction     = "context_action"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// sensitiveKeys are attribute keys whose values are never logged
+	sensitiveKeys = map[string]bool{
+		"cookie":        true,
+		"set-cookie":    true,
+		"authorization": true,
+		"api_key":       true,
+		"x-api-key":     true,
+		"session_token": true,
+		"token":         true,
+	}
+
+	requestIDRegex    = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,128}$`)
+	sessionTokenRegex = regexp.MustCompile(`session_token=[^;\s"]+`)
+	bearerRegex       = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._\-]+`)
+)
+
+type contextKey struct{}
+
+// requestFields collects fields for the request log entry
+type requestFields struct {
+	logger *slog.Logger
+	mutex  sync.Mutex
+	attrs  []slog.Attr
+}
+
+// New creates a logger writing to w. level is one of debug, info, warn or error;
+// format is json or text. Prompt fields are redacted when redactPrompts is set.
+func New(w io.Writer, level, format string, redactPrompts bool) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level: parseLevel(level),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			return redact(a, redactPrompts)
+		},
+	}
+
+	if strings.EqualFold(format, "text") {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+// Middleware assigns a request ID and writes one structured log entry per request
+func Middleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := RequestID(c.GetHeader(RequestIDHeader))
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := NewContext(c.Request.Context(), logger, requestID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("outcome", outcome(status)),
+		}
+
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.HasTraceID() {
+			attrs = append(attrs, slog.String("trace_id", spanContext.TraceID().String()))
+		}
+
+		attrs = append(attrs, Fields(ctx)...)
+
+		level := slog.LevelInfo
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		if status >= 500 {
+			level = slog.LevelError
+		} else if status >= 400 {
+			level = slog.LevelWarn
+		}
+
+		FromContext(ctx).LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// RequestID returns id when it is a valid client-supplied request ID, or a new
+// random ID otherwise
+func RequestID(id string) string {
+	if requestIDRegex.MatchString(id) {
+		return id
+	}
+	return utils.GenerateRandomID(24)
+}
+
+// NewContext returns a context carrying a request-scoped logger tagged with
+// requestID, which collects the fields recorded with Set
+func NewContext(ctx context.Context, logger *slog.Logger, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestFields{logger: logger.With("request_id", requestID)})
+}
+
+// Fields returns the fields recorded with Set for ctx
+func Fields(ctx context.Context) []slog.Attr {
+	fields, ok := ctx.Value(contextKey{}).(*requestFields)
+	if !ok {
+		return nil
+	}
+
+	fields.mutex.Lock()
+	defer fields.mutex.Unlock()
+	return append([]slog.Attr(nil), fields.attrs...)
+}
+
+// Set records a field on the request log entry for ctx
+func Set(ctx context.Context, key string, value any) {
+	fields, ok := ctx.Value(contextKey{}).(*requestFields)
+	if !ok {
+		return
+	}
+
+	fields.mutex.Lock()
+	defer fields.mutex.Unlock()
+
+	for i := range fields.attrs {
+		if fields.attrs[i].Key == key {
+			fields.attrs[i] = slog.Any(key, value)
+			return
+		}
+	}
+	fields.attrs = append(fields.attrs, slog.Any(key, value))
+}
+
+// FromContext returns the request-scoped logger for ctx, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if fields, ok := ctx.Value(contextKey{}).(*requestFields); ok {
+		return fields.logger
+	}
+	return slog.Default()
+}
+
+// redact hides sensitive attribute values and scrubs credentials from strings
+func redact(a slog.Attr, redactPrompts bool) slog.Attr {
+	key := strings.ToLower(a.Key)
+	if sensitiveKeys[key] || (redactPrompts && key == FieldPrompt) {
+		return slog.String(a.Key, redacted)
+	}
+
+	if a.Value.Kind() == slog.KindString {
+		s := a.Value.String()
+		s = sessionTokenRegex.ReplaceAllString(s, "session_token="+redacted)
+		s = bearerRegex.ReplaceAllString(s, "Bearer "+redacted)
+		return slog.String(a.Key, s)
+	}
+
+	return a
+}
+
+// outcome classifies a response status for the request log
+func outcome(status int) string {
+	switch {
+	case status >= 500:
+		return "server_error"
+	case status >= 400:
+		return "client_error"
+	default:
+		return "success"
+	}
+}
+
+// parseLevel converts a level name to a slog level, defaulting to info
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
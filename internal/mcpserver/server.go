@@ -0,0 +1,324 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ModelsURI is the URI of the resource listing the model catalog
+const ModelsURI = "akash://models"
+
+// serverVersion is the implementation version reported to MCP clients
+const serverVersion = "1.0.0"
+
+// MCP transports selectable with MCP_TRANSPORT
+const (
+	TransportOff   = "off"
+	TransportHTTP  = "http"
+	TransportStdio = "stdio"
+)
+
+// Server exposes Akash models as MCP tools and resources
+type Server struct {
+	routerService  *service.RouterService
+	akashService   *service.AkashService
+	catalogService *service.CatalogService
+	aliasService   *service.AliasService
+	imageService   *service.ImageService
+	mcpServer      *mcp.Server
+}
+
+// ChatInput is the input of the chat tool
+type ChatInput struct {
+	Model       string   `json:"model" jsonschema:"ID of the model to chat with, see list_models"`
+	Prompt      string   `json:"prompt" jsonschema:"the user message"`
+	System      string   `json:"system,omitempty" jsonschema:"optional system prompt"`
+	Temperature *float64 `json:"temperature,omitempty" jsonschema:"sampling temperature, defaults to 0.6"`
+	MaxTokens   *int     `json:"max_tokens,omitempty" jsonschema:"maximum number of tokens to generate"`
+}
+
+// ChatOutput is the result of the chat tool
+type ChatOutput struct {
+	Model        string      `json:"model"`
+	Content      string      `json:"content"`
+	FinishReason string      `json:"finish_reason"`
+	Usage        model.Usage `json:"usage"`
+}
+
+// ImageInput is the input of the generate_image tool
+type ImageInput struct {
+	Model          string `json:"model,omitempty" jsonschema:"image model or alias, defaults to AkashGen"`
+	Prompt         string `json:"prompt" jsonschema:"description of the image"`
+	NegativePrompt string `json:"negative_prompt,omitempty" jsonschema:"what the image should not contain"`
+	Size           string `json:"size,omitempty" jsonschema:"image size such as 1024x1024"`
+	AspectRatio    string `json:"aspect_ratio,omitempty" jsonschema:"aspect ratio such as 16:9, used when size is not given"`
+	Style          string `json:"style,omitempty" jsonschema:"style preset appended to the prompt"`
+	Seed           *int64 `json:"seed,omitempty" jsonschema:"seed for reproducible images"`
+}
+
+// ImageOutput is the result of the generate_image tool
+type ImageOutput struct {
+	URL            string `json:"url"`
+	JobID          string `json:"job_id"`
+	Prompt         string `json:"prompt"`
+	OriginalPrompt string `json:"original_prompt"`
+}
+
+// ListModelsInput is the input of the list_models tool
+type ListModelsInput struct {
+	IncludeUnavailable bool `json:"include_unavailable,omitempty" jsonschema:"also list models that are currently unavailable"`
+}
+
+// ModelList is the result of the list_models tool and the content of the models resource
+type ModelList struct {
+	Models []ModelInfo `json:"models"`
+}
+
+// ModelInfo describes a catalog model or alias
+type ModelInfo struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	Available     bool     `json:"available"`
+	ContextLength int      `json:"context_length,omitempty"`
+	Capabilities  []string `json:"capabilities"`
+	AliasOf       string   `json:"alias_of,omitempty"`
+}
+
+// NewServer creates a new Server instance with the chat, generate_image and
+// list_models tools and the model catalog resource registered
+func NewServer(routerService *service.RouterService, akashService *service.AkashService, catalogService *service.CatalogService, aliasService *service.AliasService, imageService *service.ImageService) *Server {
+	s := &Server{
+		routerService:  routerService,
+		akashService:   akashService,
+		catalogService: catalogService,
+		aliasService:   aliasService,
+		imageService:   imageService,
+		mcpServer:      mcp.NewServer(&mcp.Implementation{Name: "akashchat-api-go", Version: serverVersion}, nil),
+	}
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "chat",
+		Description: "Send a prompt to an Akash Chat model and return its reply",
+	}, s.chat)
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "generate_image",
+		Description: "Generate an image with AkashGen and return its URL",
+	}, s.generateImage)
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_models",
+		Description: "List the Akash Chat models and aliases that can be used with the chat tool",
+	}, s.listModels)
+
+	s.mcpServer.AddResource(&mcp.Resource{
+		URI:         ModelsURI,
+		Name:        "models",
+		Description: "The Akash Chat model catalog including unavailable models and aliases",
+		MIMEType:    "application/json",
+	}, s.readModels)
+
+	return s
+}
+
+// ValidateTransport checks that transport is a known MCP transport
+func ValidateTransport(transport string) error {
+	switch transport {
+	case TransportOff, TransportHTTP, TransportStdio:
+		return nil
+	default:
+		return fmt.Errorf("unknown MCP transport: %s", transport)
+	}
+}
+
+// RunStdio serves MCP over stdin and stdout until the client disconnects or ctx is done
+func (s *Server) RunStdio(ctx context.Context) error {
+	return s.mcpServer.Run(ctx, &mcp.StdioTransport{})
+}
+
+// HTTPHandler returns a handler serving MCP over the streamable HTTP transport
+func (s *Server) HTTPHandler() http.Handler {
+	return mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return s.mcpServer }, nil)
+}
+
+// chat implements the chat tool
+func (s *Server) chat(ctx context.Context, _ *mcp.CallToolRequest, in ChatInput) (*mcp.CallToolResult, ChatOutput, error) {
+	ctx, span := tracing.Start(ctx, "mcp.chat")
+	defer span.End()
+
+	if in.Prompt == "" {
+		return nil, ChatOutput{}, fmt.Errorf("prompt is required")
+	}
+
+	req := model.ChatCompletionRequest{
+		Model:       in.Model,
+		Temperature: in.Temperature,
+		MaxTokens:   in.MaxTokens,
+	}
+	if in.System != "" {
+		req.Messages = append(req.Messages, model.ChatMessage{Role: "system", Content: in.System})
+	}
+	req.Messages = append(req.Messages, model.ChatMessage{Role: "user", Content: in.Prompt})
+
+	if err := s.routerService.Route(ctx, &req); err != nil {
+		return nil, ChatOutput{}, routeError(in.Model, err)
+	}
+
+	if req.Model == "AkashGen" {
+		return nil, ChatOutput{}, fmt.Errorf("use the generate_image tool for image generation")
+	}
+
+	sessionToken, _, err := s.routerService.Prepare(ctx, &req)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, ChatOutput{}, err
+	}
+
+	temperature, topP := service.SamplingParams(req)
+	var generation *service.Generation
+	err = s.routerService.WithFallback(ctx, &req, nil, func(attempt model.ChatCompletionRequest) (err error) {
+		generation, err = s.akashService.GenerateText(ctx, attempt, sessionToken, temperature, topP)
+		return err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, ChatOutput{}, fmt.Errorf("text generation failed: %w", err)
+	}
+
+	out := ChatOutput{
+		Model:        req.Model,
+		Content:      generation.Content,
+		FinishReason: generation.FinishReason,
+		Usage:        generation.Usage,
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: out.Content}}}, out, nil
+}
+
+// generateImage implements the generate_image tool
+func (s *Server) generateImage(ctx context.Context, _ *mcp.CallToolRequest, in ImageInput) (*mcp.CallToolResult, ImageOutput, error) {
+	ctx, span := tracing.Start(ctx, "mcp.generate_image")
+	defer span.End()
+
+	if in.Prompt == "" {
+		return nil, ImageOutput{}, fmt.Errorf("prompt is required")
+	}
+
+	opts := &model.ImageOptions{
+		Size:           in.Size,
+		AspectRatio:    in.AspectRatio,
+		NegativePrompt: in.NegativePrompt,
+		Seed:           in.Seed,
+		Style:          in.Style,
+	}
+	if err := service.ValidateImageOptions(opts); err != nil {
+		return nil, ImageOutput{}, fmt.Errorf("invalid image parameters: %w", err)
+	}
+
+	modelID := in.Model
+	if modelID == "" {
+		modelID = "AkashGen"
+	}
+	req, err := s.routerService.Image(ctx, modelID, in.Prompt, opts)
+	if err != nil {
+		return nil, ImageOutput{}, routeError(modelID, err)
+	}
+
+	sessionToken, _, err := s.routerService.Prepare(ctx, &req)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, ImageOutput{}, err
+	}
+
+	temperature, topP := service.SamplingParams(req)
+	image, err := s.akashService.ProcessImageGeneration(ctx, req, sessionToken, temperature, topP)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, ImageOutput{}, fmt.Errorf("image generation failed: %w", err)
+	}
+
+	out := ImageOutput{
+		URL:            s.imageService.ProxyURL("", image.Pic),
+		JobID:          image.JobID,
+		Prompt:         image.Prompt,
+		OriginalPrompt: image.OriginalPrompt,
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: out.URL}}}, out, nil
+}
+
+// listModels implements the list_models tool
+func (s *Server) listModels(ctx context.Context, _ *mcp.CallToolRequest, in ListModelsInput) (*mcp.CallToolResult, ModelList, error) {
+	list, err := s.models(ctx, in.IncludeUnavailable)
+	return nil, list, err
+}
+
+// readModels serves the model catalog resource
+func (s *Server) readModels(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	list, err := s.models(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: ModelsURI, MIMEType: "application/json", Text: string(data)}},
+	}, nil
+}
+
+// models lists the catalog models followed by the aliases whose target exists
+func (s *Server) models(ctx context.Context, includeUnavailable bool) (ModelList, error) {
+	models, err := s.catalogService.Models(ctx)
+	if err != nil {
+		return ModelList{}, fmt.Errorf("failed to fetch models: %w", err)
+	}
+
+	list := ModelList{Models: []ModelInfo{}}
+	for _, m := range models {
+		if m.Available || includeUnavailable {
+			list.Models = append(list.Models, toModelInfo(m.ID, m))
+		}
+	}
+	for _, alias := range s.aliasService.Aliases() {
+		for _, m := range models {
+			if m.ID == alias.Target && (m.Available || includeUnavailable) {
+				info := toModelInfo(alias.ID, m)
+				info.AliasOf = m.ID
+				if alias.Description != "" {
+					info.Description = alias.Description
+				}
+				list.Models = append(list.Models, info)
+			}
+		}
+	}
+	return list, nil
+}
+
+// routeError returns a tool error for a routing failure of the requested modelID
+func routeError(modelID string, err error) error {
+	var notFound *service.ModelNotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("the model '%s' does not exist or is not available, use list_models to see the available models", modelID)
+	}
+	return err
+}
+
+// toModelInfo describes a catalog model, listed under id
+func toModelInfo(id string, m model.Model) ModelInfo {
+	return ModelInfo{
+		ID:            id,
+		Name:          m.Name,
+		Description:   m.Description,
+		Available:     m.Available,
+		ContextLength: m.TokenLimit,
+		Capabilities:  service.ModelCapabilities(m),
+	}
+}
@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ModelKey is the gin context key handlers use to report the requested model
+const ModelKey = "metrics.model"
+
+// OtherModel is the model label used for models missing from the catalog
+const OtherModel = "other"
+
+var (
+	knownModels map[string]bool
+	modelsMutex sync.RWMutex
+)
+
+// Upstream endpoint labels
+const (
+	UpstreamChat        = akash.EndpointChat
+	UpstreamSession     = akash.EndpointSession
+	UpstreamModels      = akash.EndpointModels
+	UpstreamImageStatus = akash.EndpointImageStatus
+	UpstreamImage       = akash.EndpointImage
+	UpstreamPing        = akash.EndpointPing
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "akashchat_http_requests_total",
+		Help: "Total number of HTTP requests by route, model and status.",
+	}, []string{"route", "model", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "akashchat_http_request_duration_seconds",
+		Help:    "HTTP request latency by route, model and status.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"route", "model", "status"})
+
+	upstreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "akashchat_upstream_request_duration_seconds",
+		Help:    "Akash upstream call latency by endpoint.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"endpoint"})
+
+	upstreamErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "akashchat_upstream_errors_total",
+		Help: "Akash upstream call failures by endpoint.",
+	}, []string{"endpoint"})
+
+	streamTimeToFirstToken = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "akashchat_stream_time_to_first_token_seconds",
+		Help:    "Time from upstream request to the first streamed content chunk.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 4, 8, 15, 30},
+	}, []string{"model"})
+
+	streamTokensPerSecond = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "akashchat_stream_tokens_per_second",
+		Help:    "Streamed completion tokens per second after the first token.",
+		Buckets: []float64{1, 5, 10, 20, 40, 60, 100, 200},
+	}, []string{"model"})
+
+	sessionRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "akashchat_session_refreshes_total",
+		Help: "Session token refreshes by result.",
+	}, []string{"result"})
+
+	imageQueueDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "akashchat_image_queue_duration_seconds",
+		Help:    "Time an image job spent queued before a worker picked it up.",
+		Buckets: []float64{0.5, 1, 2, 5, 10, 20, 30, 60},
+	})
+
+	imagePollDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "akashchat_image_poll_duration_seconds",
+		Help:    "Total time spent polling an image job by result.",
+		Buckets: []float64{1, 2, 5, 10, 20, 30, 45, 60},
+	}, []string{"result"})
+)
+
+// Handler returns the handler for the /metrics endpoint
+func Handler() gin.HandlerFunc {
+	h := promhttp.Handler()
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// Middleware records request counts and latency for every route
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		model := modelLabel(c.GetString(ModelKey))
+
+		requestsTotal.WithLabelValues(route, model, status).Inc()
+		requestDuration.WithLabelValues(route, model, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// observeUpstream records an upstream call that took elapsed and returned status
+func observeUpstream(endpoint string, elapsed time.Duration, status int, err error) {
+	upstreamDuration.WithLabelValues(endpoint).Observe(elapsed.Seconds())
+	if err != nil || status >= http.StatusBadRequest {
+		upstreamErrors.WithLabelValues(endpoint).Inc()
+	}
+}
+
+// AkashHooks returns hooks that record the calls made by an Akash client
+func AkashHooks() akash.Hooks {
+	return akash.Hooks{
+		Upstream:       observeUpstream,
+		SessionRefresh: ObserveSessionRefresh,
+		ImageJob:       ObserveImageJob,
+	}
+}
+
+// SetModels sets the catalog models that are used as label values. Any other
+// model is labelled OtherModel, so clients cannot create arbitrary series.
+func SetModels(ids []string) {
+	models := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		models[id] = true
+	}
+
+	modelsMutex.Lock()
+	knownModels = models
+	modelsMutex.Unlock()
+}
+
+// modelLabel returns the label value for model
+func modelLabel(model string) string {
+	if model == "" {
+		return ""
+	}
+
+	modelsMutex.RLock()
+	defer modelsMutex.RUnlock()
+
+	if knownModels[model] {
+		return model
+	}
+	return OtherModel
+}
+
+// ObserveStream records time-to-first-token and throughput for a completed stream
+func ObserveStream(model string, start, firstToken time.Time, tokens int) {
+	if firstToken.IsZero() {
+		return
+	}
+
+	model = modelLabel(model)
+	streamTimeToFirstToken.WithLabelValues(model).Observe(firstToken.Sub(start).Seconds())
+	if elapsed := time.Since(firstToken).Seconds(); elapsed > 0 && tokens > 1 {
+		streamTokensPerSecond.WithLabelValues(model).Observe(float64(tokens-1) / elapsed)
+	}
+}
+
+// ObserveSessionRefresh records a session token refresh
+func ObserveSessionRefresh(err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	sessionRefreshes.WithLabelValues(result).Inc()
+}
+
+// ObserveImageJob records queue and total poll durations for an image job
+func ObserveImageJob(queued, total time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	if queued > 0 {
+		imageQueueDuration.Observe(queued.Seconds())
+	}
+	imagePollDuration.WithLabelValues(result).Observe(total.Seconds())
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import "testing"
+
+func TestModelLabel(t *testing.T) {
+	SetModels([]string{"Meta-Llama-3-3-70B-Instruct"})
+	defer SetModels(nil)
+
+	tests := map[string]string{
+		"":                            "",
+		"Meta-Llama-3-3-70B-Instruct": "Meta-Llama-3-3-70B-Instruct",
+		"made-up-model-1234":          OtherModel,
+	}
+	for model, want := range tests {
+		if got := modelLabel(model); got != want {
+			t.Errorf("modelLabel(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AnthropicMessagesRequest represents an incoming Anthropic /v1/messages request
+type AnthropicMessagesRequest struct {
+	Model         string             `json:"model" binding:"required"`
+	Messages      []AnthropicMessage `json:"messages" binding:"required"`
+	System        AnthropicContent   `json:"system,omitempty"`
+	MaxTokens     int                `json:"max_tokens" binding:"required"`
+	StopSequences []string           `json:"stop_sequences,omitempty"`
+	Stream        bool               `json:"stream,omitempty"`
+	Temperature   *float64           `json:"temperature,omitempty"`
+	TopP          *float64           `json:"top_p,omitempty"`
+}
+
+// AnthropicMessage represents a single conversation turn
+type AnthropicMessage struct {
+	Role    string           `json:"role" binding:"required"`
+	Content AnthropicContent `json:"content" binding:"required"`
+}
+
+// AnthropicContent is message content given either as a plain string or as a
+// list of content blocks
+type AnthropicContent []AnthropicContentBlock
+
+// AnthropicContentBlock represents a single content block
+type AnthropicContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// UnmarshalJSON decodes either a string or an array of content blocks
+func (c *AnthropicContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = AnthropicContent{{Type: "text", Text: text}}
+		return nil
+	}
+
+	var blocks []AnthropicContentBlock
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return fmt.Errorf("content must be a string or an array of content blocks")
+	}
+	*c = blocks
+	return nil
+}
+
+// AnthropicMessagesResponse represents the Anthropic /v1/messages response
+type AnthropicMessagesResponse struct {
+	ID           string                  `json:"id"`
+	Type         string                  `json:"type"`
+	Role         string                  `json:"role"`
+	Model        string                  `json:"model"`
+	Content      []AnthropicContentBlock `json:"content"`
+	StopReason   *string                 `json:"stop_reason"`
+	StopSequence *string                 `json:"stop_sequence"`
+	Usage        AnthropicUsage          `json:"usage"`
+}
+
+// AnthropicUsage represents Anthropic token usage
+type AnthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// AnthropicStreamEvent represents a server-sent event in an Anthropic stream.
+// Only the fields relevant to Type are set.
+type AnthropicStreamEvent struct {
+	Type         string                     `json:"type"`
+	Message      *AnthropicMessagesResponse `json:"message,omitempty"`
+	Index        *int                       `json:"index,omitempty"`
+	ContentBlock *AnthropicContentBlock     `json:"content_block,omitempty"`
+	Delta        *AnthropicDelta            `json:"delta,omitempty"`
+	Usage        *AnthropicUsage            `json:"usage,omitempty"`
+	Error        *AnthropicError            `json:"error,omitempty"`
+}
+
+// AnthropicDelta represents the delta of a content_block_delta or message_delta event
+type AnthropicDelta struct {
+	Type         string  `json:"type,omitempty"`
+	Text         string  `json:"text,omitempty"`
+	StopReason   *string `json:"stop_reason,omitempty"`
+	StopSequence *string `json:"stop_sequence,omitempty"`
+}
+
+// AnthropicError represents an Anthropic error object
+type AnthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// AnthropicErrorResponse represents an Anthropic error response
+type AnthropicErrorResponse struct {
+	Type  string         `json:"type"`
+	Error AnthropicError `json:"error"`
+}
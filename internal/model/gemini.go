@@ -0,0 +1,70 @@
+package model
+
+// GeminiRequest represents an incoming Gemini generateContent request. The
+// REST API accepts both camelCase and snake_case field names.
+type GeminiRequest struct {
+	Contents               []GeminiContent         `json:"contents"`
+	SystemInstruction      *GeminiContent          `json:"systemInstruction,omitempty"`
+	SystemInstructionSnake *GeminiContent          `json:"system_instruction,omitempty"`
+	GenerationConfig       *GeminiGenerationConfig `json:"generationConfig,omitempty"`
+	GenerationConfigSnake  *GeminiGenerationConfig `json:"generation_config,omitempty"`
+}
+
+// GeminiContent represents a conversation turn made of parts
+type GeminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []GeminiPart `json:"parts"`
+}
+
+// GeminiPart represents a single part of a content. Only text parts are
+// supported; the remaining fields are decoded so they can be rejected.
+type GeminiPart struct {
+	Text             string      `json:"text,omitempty"`
+	InlineData       interface{} `json:"inlineData,omitempty"`
+	FileData         interface{} `json:"fileData,omitempty"`
+	FunctionCall     interface{} `json:"functionCall,omitempty"`
+	FunctionResponse interface{} `json:"functionResponse,omitempty"`
+}
+
+// GeminiGenerationConfig represents the generation parameters of a request
+type GeminiGenerationConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"topP,omitempty"`
+	MaxOutputTokens *int     `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+	CandidateCount  int      `json:"candidateCount,omitempty"`
+}
+
+// GeminiResponse represents a generateContent response or stream chunk
+type GeminiResponse struct {
+	Candidates    []GeminiCandidate    `json:"candidates"`
+	UsageMetadata *GeminiUsageMetadata `json:"usageMetadata,omitempty"`
+	ModelVersion  string               `json:"modelVersion"`
+	ResponseID    string               `json:"responseId,omitempty"`
+}
+
+// GeminiCandidate represents a generated candidate
+type GeminiCandidate struct {
+	Content      GeminiContent `json:"content"`
+	FinishReason string        `json:"finishReason,omitempty"`
+	Index        int           `json:"index"`
+}
+
+// GeminiUsageMetadata represents Gemini token usage
+type GeminiUsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+// GeminiError represents a Gemini error object
+type GeminiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+// GeminiErrorResponse represents a Gemini error response
+type GeminiErrorResponse struct {
+	Error GeminiError `json:"error"`
+}
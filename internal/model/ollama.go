@@ -0,0 +1,95 @@
+package model
+
+// OllamaOptions represents the model options of an Ollama request. Options
+// without an Akash equivalent are ignored.
+type OllamaOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	NumPredict  *int     `json:"num_predict,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+// OllamaMessage represents a message in an Ollama chat request or response
+type OllamaMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
+}
+
+// OllamaChatRequest represents an incoming Ollama /api/chat request
+type OllamaChatRequest struct {
+	Model    string          `json:"model" binding:"required"`
+	Messages []OllamaMessage `json:"messages"`
+	Stream   *bool           `json:"stream,omitempty"`
+	Options  OllamaOptions   `json:"options,omitempty"`
+}
+
+// OllamaGenerateRequest represents an incoming Ollama /api/generate request
+type OllamaGenerateRequest struct {
+	Model   string        `json:"model" binding:"required"`
+	Prompt  string        `json:"prompt"`
+	System  string        `json:"system,omitempty"`
+	Images  []string      `json:"images,omitempty"`
+	Stream  *bool         `json:"stream,omitempty"`
+	Options OllamaOptions `json:"options,omitempty"`
+}
+
+// OllamaResponse represents a chat or generate response object. Streaming
+// responses send one object per chunk, with timing and token counts on the
+// final object where Done is true.
+type OllamaResponse struct {
+	Model              string         `json:"model"`
+	CreatedAt          string         `json:"created_at"`
+	Message            *OllamaMessage `json:"message,omitempty"`
+	Response           *string        `json:"response,omitempty"`
+	Done               bool           `json:"done"`
+	DoneReason         string         `json:"done_reason,omitempty"`
+	TotalDuration      int64          `json:"total_duration,omitempty"`
+	LoadDuration       int64          `json:"load_duration,omitempty"`
+	PromptEvalCount    int            `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64          `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int            `json:"eval_count,omitempty"`
+	EvalDuration       int64          `json:"eval_duration,omitempty"`
+}
+
+// OllamaShowRequest represents an incoming Ollama /api/show request
+type OllamaShowRequest struct {
+	Model string `json:"model"`
+	Name  string `json:"name"`
+}
+
+// OllamaModelDetails represents the details of an Ollama model
+type OllamaModelDetails struct {
+	ParentModel       string   `json:"parent_model"`
+	Format            string   `json:"format"`
+	Family            string   `json:"family"`
+	Families          []string `json:"families"`
+	ParameterSize     string   `json:"parameter_size"`
+	QuantizationLevel string   `json:"quantization_level"`
+}
+
+// OllamaModel represents a model in the Ollama /api/tags response
+type OllamaModel struct {
+	Name       string             `json:"name"`
+	Model      string             `json:"model"`
+	ModifiedAt string             `json:"modified_at"`
+	Size       int64              `json:"size"`
+	Digest     string             `json:"digest"`
+	Details    OllamaModelDetails `json:"details"`
+}
+
+// OllamaTagsResponse represents the Ollama /api/tags response
+type OllamaTagsResponse struct {
+	Models []OllamaModel `json:"models"`
+}
+
+// OllamaShowResponse represents the Ollama /api/show response
+type OllamaShowResponse struct {
+	Modelfile    string                 `json:"modelfile"`
+	Parameters   string                 `json:"parameters"`
+	Template     string                 `json:"template"`
+	Details      OllamaModelDetails     `json:"details"`
+	ModelInfo    map[string]interface{} `json:"model_info"`
+	Capabilities []string               `json:"capabilities"`
+	ModifiedAt   string                 `json:"modified_at"`
+}
@@ -1,5 +1,11 @@
 package model
 
+import (
+	"encoding/json"
+
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+)
+
 // ChatMessage represents a chat message
 type ChatMessage struct {
 	Role    string `json:"role" binding:"required"`
@@ -13,28 +19,67 @@ type ChatCompletionRequest struct {
 	Temperature *float64      `json:"temperature,omitempty"`
 	TopP        *float64      `json:"topP,omitempty"`
 	Stream      *bool         `json:"stream,omitempty"`
+	CallbackURL string        `json:"callback_url,omitempty"`
+	Image       *ImageOptions `json:"image,omitempty"`
+	MaxTokens   *int          `json:"max_tokens,omitempty"`
+	Stop        StringList    `json:"stop,omitempty"`
+
+	// SystemPrompt overrides the default Akash system prompt; set by virtual models
+	SystemPrompt string `json:"-"`
+	// Fallbacks are the models tried in order when Model fails
+	Fallbacks []string `json:"-"`
+	// OriginalMessages are the messages before they were fitted into the context
+	// window of Model, kept so they can be fitted again for a fallback model
+	OriginalMessages []ChatMessage `json:"-"`
 }
 
-// AkashChatRequest represents the request to Akash API
-type AkashChatRequest struct {
-	ID          string        `json:"id"`
-	Messages    []ChatMessage `json:"messages"`
-	Model       string        `json:"model"`
-	System      string        `json:"system"`
-	Temperature float64       `json:"temperature"`
-	TopP        float64       `json:"topP"`
-	Context     []interface{} `json:"context"`
+// StringList is a list of strings that also accepts a single JSON string
+type StringList []string
+
+// UnmarshalJSON decodes either a string or an array of strings
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = StringList{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
 }
 
-// ImageStatusResponse represents the image generation status response
-type ImageStatusResponse struct {
-	JobID         string  `json:"job_id"`
-	WorkerName    string  `json:"worker_name"`
-	WorkerCity    string  `json:"worker_city"`
-	WorkerCountry string  `json:"worker_country"`
-	Status        string  `json:"status"`
-	Result        string  `json:"result"`
-	WorkerGPU     string  `json:"worker_gpu"`
-	ElapsedTime   float64 `json:"elapsed_time"`
-	QueuePosition int     `json:"queue_position"`
-}
\ No newline at end of file
+// ImageOptions represents structured parameters for image generation
+type ImageOptions = akash.ImageOptions
+
+// ImageGenerationRequest represents an incoming /v1/images/generations request
+type ImageGenerationRequest struct {
+	Prompt string `json:"prompt" binding:"required"`
+	Model  string `json:"model,omitempty"`
+	ImageOptions
+}
+
+// TokenizeRequest represents an incoming /v1/tokenize request. Either Input or
+// Messages must be set.
+type TokenizeRequest struct {
+	Model    string        `json:"model" binding:"required"`
+	Input    string        `json:"input,omitempty"`
+	Messages []ChatMessage `json:"messages,omitempty"`
+}
+
+// CompletionRequest represents an incoming legacy /v1/completions request.
+// Each prompt is sent as a single user message.
+type CompletionRequest struct {
+	Model       string     `json:"model" binding:"required"`
+	Prompt      StringList `json:"prompt" binding:"required"`
+	Suffix      string     `json:"suffix,omitempty"`
+	MaxTokens   *int       `json:"max_tokens,omitempty"`
+	Temperature *float64   `json:"temperature,omitempty"`
+	TopP        *float64   `json:"top_p,omitempty"`
+	Stream      bool       `json:"stream,omitempty"`
+	Echo        bool       `json:"echo,omitempty"`
+	Stop        StringList `json:"stop,omitempty"`
+}
@@ -8,15 +8,32 @@ type APIResponse struct {
 
 // ImageGenerationData represents response data for image generation
 type ImageGenerationData struct {
-	Model  string `json:"model"`
-	JobID  string `json:"jobId"`
-	Prompt string `json:"prompt"`
-	Pic    string `json:"pic"`
+	Model          string `json:"model"`
+	JobID          string `json:"jobId"`
+	Prompt         string `json:"prompt"`
+	OriginalPrompt string `json:"originalPrompt"`
+	Pic            string `json:"pic"`
+}
+
+// OpenAIImagesResponse represents the image generation response in OpenAI format.
+type OpenAIImagesResponse struct {
+	Created int64         `json:"created"`
+	Data    []OpenAIImage `json:"data"`
+}
+
+// OpenAIImage represents a single generated image in OpenAI format.
+type OpenAIImage struct {
+	URL            string `json:"url"`
+	RevisedPrompt  string `json:"revised_prompt"`
+	OriginalPrompt string `json:"original_prompt"`
+	JobID          string `json:"job_id"`
 }
 
 // ErrorData represents error response data
 type ErrorData struct {
-	Message string `json:"msg"`
+	Message     string   `json:"msg"`
+	Type        string   `json:"type,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 // SessionResponse represents the session response from Akash
@@ -54,6 +71,16 @@ type Usage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
+// TokenizeResponse represents the /v1/tokenize response
+type TokenizeResponse struct {
+	Model      string `json:"model"`
+	Tokenizer  string `json:"tokenizer"`
+	Count      int    `json:"count"`
+	Estimated  bool   `json:"estimated"`
+	TokenLimit int    `json:"token_limit,omitempty"`
+	Tokens     []int  `json:"tokens,omitempty"`
+}
+
 // OpenAIStreamCompletion represents the chat completion response in OpenAI stream format.
 type OpenAIStreamCompletion struct {
 	ID      string               `json:"id"`
@@ -61,6 +88,7 @@ type OpenAIStreamCompletion struct {
 	Created int64                `json:"created"`
 	Model   string               `json:"model"`
 	Choices []OpenAIStreamChoice `json:"choices"`
+	Usage   *Usage               `json:"usage,omitempty"`
 }
 
 // OpenAIStreamChoice represents a single choice in the chat completion stream response.
@@ -75,6 +103,25 @@ type Delta struct {
 	Role    string `json:"role,omitempty"`
 	Content string `json:"content,omitempty"`
 }
+
+// TextCompletion represents a legacy completion response or stream chunk
+type TextCompletion struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []TextCompletionChoice `json:"choices"`
+	Usage   *Usage                 `json:"usage,omitempty"`
+}
+
+// TextCompletionChoice represents a single choice in a legacy completion response
+type TextCompletionChoice struct {
+	Text         string      `json:"text"`
+	Index        int         `json:"index"`
+	Logprobs     interface{} `json:"logprobs"`
+	FinishReason string      `json:"finish_reason,omitempty"`
+}
+
 // OpenAIModel represents a single model in the OpenAI models list.
 type OpenAIModel struct {
 	ID         string      `json:"id"`
@@ -90,4 +137,73 @@ type OpenAIModel struct {
 type OpenAIModelsList struct {
 	Object string        `json:"object"`
 	Data   []OpenAIModel `json:"data"`
-}
\ No newline at end of file
+}
+
+// ExtendedModel represents an OpenAI model entry enriched with Akash metadata.
+type ExtendedModel struct {
+	OpenAIModel
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	TokenLimit   int      `json:"token_limit,omitempty"`
+	Parameters   string   `json:"parameters,omitempty"`
+	Architecture string   `json:"architecture,omitempty"`
+	HFRepo       string   `json:"hf_repo,omitempty"`
+	Available    bool     `json:"available"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// ExtendedModelsList represents the list of extended models.
+type ExtendedModelsList struct {
+	Object string          `json:"object"`
+	Data   []ExtendedModel `json:"data"`
+}
+
+// AsyncJobData represents response data for a request accepted for asynchronous processing
+type AsyncJobData struct {
+	JobID       string `json:"jobId"`
+	Status      string `json:"status"`
+	CallbackURL string `json:"callbackUrl"`
+}
+
+// WebhookPayload represents the JSON body delivered to a callback URL
+type WebhookPayload struct {
+	ID      string      `json:"id"`
+	Event   string      `json:"event"`
+	Model   string      `json:"model"`
+	Created int64       `json:"created"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// WebhookAttempt represents a single webhook delivery attempt
+type WebhookAttempt struct {
+	Attempt    int    `json:"attempt"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// WebhookDelivery represents the delivery state of a webhook payload
+type WebhookDelivery struct {
+	ID          string           `json:"id"`
+	Event       string           `json:"event"`
+	CallbackURL string           `json:"callbackUrl"`
+	Status      string           `json:"status"`
+	Attempts    []WebhookAttempt `json:"attempts"`
+}
+
+// HealthCheck represents the result of a single readiness check
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	Message   string  `json:"message,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+	CheckedAt int64   `json:"checkedAt"`
+}
+
+// ReadinessReport represents the detailed readiness response
+type ReadinessReport struct {
+	Status    string                 `json:"status"`
+	Checks    map[string]HealthCheck `json:"checks"`
+	CheckedAt int64                  `json:"checkedAt"`
+	Cached    bool                   `json:"cached"`
+}
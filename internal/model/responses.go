@@ -0,0 +1,149 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ResponsesRequest represents an incoming OpenAI /v1/responses request
+type ResponsesRequest struct {
+	Model              string            `json:"model" binding:"required"`
+	Input              ResponseInput     `json:"input" binding:"required"`
+	Instructions       string            `json:"instructions,omitempty"`
+	PreviousResponseID string            `json:"previous_response_id,omitempty"`
+	Stream             bool              `json:"stream,omitempty"`
+	Store              *bool             `json:"store,omitempty"`
+	Temperature        *float64          `json:"temperature,omitempty"`
+	TopP               *float64          `json:"top_p,omitempty"`
+	MaxOutputTokens    *int              `json:"max_output_tokens,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+// ResponseInput is the request input given either as a plain string or as a
+// list of input items
+type ResponseInput []ResponseInputItem
+
+// ResponseInputItem represents a single input item. Only message items are supported.
+type ResponseInputItem struct {
+	Type    string          `json:"type,omitempty"`
+	Role    string          `json:"role"`
+	Content ResponseContent `json:"content"`
+}
+
+// ResponseContent is message content given either as a plain string or as a
+// list of content parts
+type ResponseContent []ResponseContentPart
+
+// ResponseContentPart represents a single content part of an input message
+type ResponseContentPart struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// UnmarshalJSON decodes either a string or an array of input items
+func (i *ResponseInput) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*i = ResponseInput{{Type: "message", Role: "user", Content: ResponseContent{{Type: "input_text", Text: text}}}}
+		return nil
+	}
+
+	var items []ResponseInputItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("input must be a string or an array of input items")
+	}
+	*i = items
+	return nil
+}
+
+// UnmarshalJSON decodes either a string or an array of content parts
+func (c *ResponseContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = ResponseContent{{Type: "input_text", Text: text}}
+		return nil
+	}
+
+	var parts []ResponseContentPart
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return fmt.Errorf("content must be a string or an array of content parts")
+	}
+	*c = parts
+	return nil
+}
+
+// Response represents an OpenAI response object
+type Response struct {
+	ID                 string                     `json:"id"`
+	Object             string                     `json:"object"`
+	CreatedAt          int64                      `json:"created_at"`
+	Status             string                     `json:"status"`
+	Model              string                     `json:"model"`
+	Output             []ResponseOutputItem       `json:"output"`
+	Instructions       *string                    `json:"instructions"`
+	PreviousResponseID *string                    `json:"previous_response_id"`
+	IncompleteDetails  *ResponseIncompleteDetails `json:"incomplete_details"`
+	Error              *ResponseError             `json:"error"`
+	Temperature        *float64                   `json:"temperature"`
+	TopP               *float64                   `json:"top_p"`
+	MaxOutputTokens    *int                       `json:"max_output_tokens"`
+	Store              bool                       `json:"store"`
+	Metadata           map[string]string          `json:"metadata"`
+	Usage              *ResponseUsage             `json:"usage"`
+}
+
+// ResponseOutputItem represents an output message of a response
+type ResponseOutputItem struct {
+	Type    string               `json:"type"`
+	ID      string               `json:"id"`
+	Status  string               `json:"status"`
+	Role    string               `json:"role"`
+	Content []ResponseOutputText `json:"content"`
+}
+
+// ResponseOutputText represents an output_text content part
+type ResponseOutputText struct {
+	Type        string        `json:"type"`
+	Text        string        `json:"text"`
+	Annotations []interface{} `json:"annotations"`
+}
+
+// ResponseIncompleteDetails explains why a response is incomplete
+type ResponseIncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+// ResponseError represents the error of a failed response
+type ResponseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ResponseUsage represents Responses API token usage
+type ResponseUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// ResponseStreamEvent represents a semantic event in a Responses API stream.
+// Only the fields relevant to Type are set.
+type ResponseStreamEvent struct {
+	Type           string              `json:"type"`
+	SequenceNumber int                 `json:"sequence_number"`
+	Response       *Response           `json:"response,omitempty"`
+	OutputIndex    *int                `json:"output_index,omitempty"`
+	ContentIndex   *int                `json:"content_index,omitempty"`
+	ItemID         string              `json:"item_id,omitempty"`
+	Item           *ResponseOutputItem `json:"item,omitempty"`
+	Part           *ResponseOutputText `json:"part,omitempty"`
+	Delta          string              `json:"delta,omitempty"`
+	Text           *string             `json:"text,omitempty"`
+}
+
+// ResponseDeleted represents the result of deleting a stored response
+type ResponseDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
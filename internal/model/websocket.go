@@ -0,0 +1,22 @@
+package model
+
+// WSClientFrame represents a frame sent by a WebSocket chat client. Chat and
+// cancel frames carry the client-chosen ID of the generation they refer to.
+type WSClientFrame struct {
+	Type    string                 `json:"type"`
+	ID      string                 `json:"id,omitempty"`
+	Request *ChatCompletionRequest `json:"request,omitempty"`
+}
+
+// WSServerFrame represents a frame sent to a WebSocket chat client. Only the
+// fields relevant to Type are set.
+type WSServerFrame struct {
+	Type         string     `json:"type"`
+	ID           string     `json:"id,omitempty"`
+	Model        string     `json:"model,omitempty"`
+	MessageID    string     `json:"message_id,omitempty"`
+	Content      string     `json:"content,omitempty"`
+	FinishReason string     `json:"finish_reason,omitempty"`
+	Usage        *Usage     `json:"usage,omitempty"`
+	Error        *ErrorData `json:"error,omitempty"`
+}
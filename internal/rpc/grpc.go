@@ -0,0 +1,150 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/auth"
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	akashchatv1 "github.com/006lp/akashchat-api-go/pkg/pb/akashchat/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// NewGRPCServer creates a gRPC server serving s together with the standard health
+// service and server reflection, so tools like grpcurl can discover the API. When
+// keys are configured every call except health checks must carry one.
+func NewGRPCServer(s *Server, logger *slog.Logger, keys *auth.Keys) *grpc.Server {
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLogger(logger), unaryAuth(keys)),
+		grpc.ChainStreamInterceptor(streamLogger(logger), streamAuth(keys)),
+	)
+
+	akashchatv1.RegisterAkashChatServer(grpcServer, s)
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	reflection.Register(grpcServer)
+	return grpcServer
+}
+
+// unaryLogger writes one structured log entry per unary call
+func unaryLogger(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = startCall(ctx, logger)
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// streamLogger writes one structured log entry per streaming call
+func streamLogger(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := startCall(ss.Context(), logger)
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// contextStream is a server stream with a replaced context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// startCall assigns the call a request ID, taken from the x-request-id metadata
+// when valid, returns it in the response headers and stores a request-scoped
+// logger in the returned context
+func startCall(ctx context.Context, logger *slog.Logger) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := ""
+	if values := md.Get(logging.RequestIDHeader); len(values) > 0 {
+		requestID = values[0]
+	}
+	requestID = logging.RequestID(requestID)
+
+	grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDHeader, requestID))
+	return logging.NewContext(ctx, logger, requestID)
+}
+
+// unaryAuth rejects unary calls without a valid API key
+func unaryAuth(keys *auth.Keys) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authenticate(ctx, keys, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuth rejects streaming calls without a valid API key
+func streamAuth(keys *auth.Keys) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authenticate(ss.Context(), keys, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authenticate checks the API key in the call metadata, using the same headers
+// as the HTTP API. Health checks are always allowed.
+func authenticate(ctx context.Context, keys *auth.Keys, method string) error {
+	if !keys.Enabled() || strings.HasPrefix(method, "/grpc.health.v1.Health/") {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := auth.FromHeaders(func(name string) string {
+		if values := md.Get(name); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	})
+	name, ok := keys.Lookup(key)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "invalid or missing API key")
+	}
+	logging.Set(ctx, logging.FieldKeyName, name)
+	return nil
+}
+
+// logCall logs a finished call at a level matching its status code, together
+// with the fields recorded for it
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+	}
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	attrs = append(attrs, logging.Fields(ctx)...)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, level, "rpc", attrs...)
+}
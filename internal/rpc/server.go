@@ -0,0 +1,294 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	akashchatv1 "github.com/006lp/akashchat-api-go/pkg/pb/akashchat/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the AkashChat gRPC service
+type Server struct {
+	akashchatv1.UnimplementedAkashChatServer
+
+	routerService  *service.RouterService
+	akashService   *service.AkashService
+	catalogService *service.CatalogService
+	aliasService   *service.AliasService
+	imageService   *service.ImageService
+}
+
+// NewServer creates a new Server instance
+func NewServer(routerService *service.RouterService, akashService *service.AkashService, catalogService *service.CatalogService, aliasService *service.AliasService, imageService *service.ImageService) *Server {
+	return &Server{
+		routerService:  routerService,
+		akashService:   akashService,
+		catalogService: catalogService,
+		aliasService:   aliasService,
+		imageService:   imageService,
+	}
+}
+
+// ListModels lists the catalog models followed by the aliases whose target exists
+func (s *Server) ListModels(ctx context.Context, req *akashchatv1.ListModelsRequest) (*akashchatv1.ListModelsResponse, error) {
+	models, err := s.catalogService.Models(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to fetch models: %v", err)
+	}
+
+	resp := &akashchatv1.ListModelsResponse{}
+	for _, m := range models {
+		if m.Available || req.IncludeUnavailable {
+			resp.Models = append(resp.Models, toModel(m.ID, m))
+		}
+	}
+	for _, alias := range s.aliasService.Aliases() {
+		for _, m := range models {
+			if m.ID == alias.Target && (m.Available || req.IncludeUnavailable) {
+				pm := toModel(alias.ID, m)
+				pm.AliasOf = m.ID
+				if alias.Description != "" {
+					pm.Description = alias.Description
+				}
+				resp.Models = append(resp.Models, pm)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// ChatCompletion generates a complete chat reply
+func (s *Server) ChatCompletion(ctx context.Context, req *akashchatv1.ChatCompletionRequest) (*akashchatv1.ChatCompletionResponse, error) {
+	ctx, span := tracing.Start(ctx, "rpc.ChatCompletion")
+	defer span.End()
+
+	chatReq, sessionToken, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	temperature, topP := service.SamplingParams(chatReq)
+	var generation *service.Generation
+	err = s.routerService.WithFallback(ctx, &chatReq, nil, func(attempt model.ChatCompletionRequest) (err error) {
+		generation, err = s.akashService.GenerateText(ctx, attempt, sessionToken, temperature, topP)
+		return err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, generationError(err)
+	}
+
+	return &akashchatv1.ChatCompletionResponse{
+		Id:           "chatcmpl-" + generation.MessageID,
+		Model:        chatReq.Model,
+		Created:      time.Now().Unix(),
+		Content:      generation.Content,
+		FinishReason: generation.FinishReason,
+		Usage:        toUsage(generation.Usage),
+	}, nil
+}
+
+// StreamChatCompletion streams a chat reply as it is generated
+func (s *Server) StreamChatCompletion(req *akashchatv1.ChatCompletionRequest, stream akashchatv1.AkashChat_StreamChatCompletionServer) error {
+	ctx, span := tracing.Start(stream.Context(), "rpc.StreamChatCompletion")
+	defer span.End()
+
+	chatReq, sessionToken, err := s.prepare(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	temperature, topP := service.SamplingParams(chatReq)
+	var id string
+	started := false
+	err = s.routerService.WithFallback(ctx, &chatReq, func() bool { return started }, func(attempt model.ChatCompletionRequest) error {
+		return s.akashService.StreamTextGeneration(ctx, attempt, sessionToken, temperature, topP, func(event service.StreamEvent) error {
+			switch event.Type {
+			case service.StreamStart:
+				started = true
+				id = "chatcmpl-" + event.MessageID
+			case service.StreamDelta:
+				return stream.Send(&akashchatv1.ChatCompletionChunk{Id: id, Model: attempt.Model, Content: event.Content})
+			case service.StreamFinish:
+				return stream.Send(&akashchatv1.ChatCompletionChunk{
+					Id:           id,
+					Model:        attempt.Model,
+					FinishReason: event.FinishReason,
+					Usage:        toUsage(event.Usage),
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return generationError(err)
+	}
+	return nil
+}
+
+// GenerateImage generates images with AkashGen. Image URLs point to the HTTP
+// image proxy only when a public base URL is configured: unlike an HTTP request,
+// a gRPC call does not reveal the address clients reach the HTTP API under, so
+// the Akash URLs are returned otherwise.
+func (s *Server) GenerateImage(ctx context.Context, req *akashchatv1.GenerateImageRequest) (*akashchatv1.GenerateImageResponse, error) {
+	ctx, span := tracing.Start(ctx, "rpc.GenerateImage")
+	defer span.End()
+
+	if req.Prompt == "" {
+		return nil, status.Error(codes.InvalidArgument, "prompt is required")
+	}
+
+	opts := &model.ImageOptions{
+		Size:           req.Size,
+		AspectRatio:    req.AspectRatio,
+		NegativePrompt: req.NegativePrompt,
+		Seed:           req.Seed,
+		Style:          req.Style,
+		N:              int(req.N),
+		Verbatim:       req.VerbatimPrompt,
+	}
+	if err := service.ValidateImageOptions(opts); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid image parameters: %v", err)
+	}
+
+	modelID := req.Model
+	if modelID == "" {
+		modelID = "AkashGen"
+	}
+	chatReq, err := s.routerService.Image(ctx, modelID, req.Prompt, opts)
+	if err != nil {
+		return nil, routeError(modelID, err)
+	}
+
+	sessionToken, _, err := s.routerService.Prepare(ctx, &chatReq)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, prepareError(err)
+	}
+
+	temperature, topP := service.SamplingParams(chatReq)
+	images, err := s.akashService.ProcessImageGenerations(ctx, chatReq, sessionToken, temperature, topP)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, generationError(err)
+	}
+
+	resp := &akashchatv1.GenerateImageResponse{}
+	for _, image := range images {
+		resp.Images = append(resp.Images, &akashchatv1.Image{
+			JobId:          image.JobID,
+			Url:            s.imageService.ProxyURL("", image.Pic),
+			Prompt:         image.Prompt,
+			OriginalPrompt: image.OriginalPrompt,
+		})
+	}
+	return resp, nil
+}
+
+// prepare converts a chat request, resolves its model and fallbacks, gets a
+// session token and fits the prompt into the model's context window
+func (s *Server) prepare(ctx context.Context, req *akashchatv1.ChatCompletionRequest) (model.ChatCompletionRequest, string, error) {
+	chatReq := model.ChatCompletionRequest{
+		Model:       req.Model,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stop:        req.Stop,
+	}
+	if req.MaxTokens != nil {
+		maxTokens := int(*req.MaxTokens)
+		chatReq.MaxTokens = &maxTokens
+	}
+	for _, msg := range req.Messages {
+		chatReq.Messages = append(chatReq.Messages, model.ChatMessage{Role: msg.Role, Content: msg.Content})
+	}
+
+	if chatReq.Model == "" {
+		return chatReq, "", status.Error(codes.InvalidArgument, "model is required")
+	}
+	if len(chatReq.Messages) == 0 {
+		return chatReq, "", status.Error(codes.InvalidArgument, "messages are required")
+	}
+
+	requested := chatReq.Model
+	if err := s.routerService.Route(ctx, &chatReq); err != nil {
+		return chatReq, "", routeError(requested, err)
+	}
+
+	if chatReq.Model == "AkashGen" {
+		return chatReq, "", status.Error(codes.InvalidArgument, "use GenerateImage for image generation")
+	}
+
+	sessionToken, _, err := s.routerService.Prepare(ctx, &chatReq)
+	if err != nil {
+		return chatReq, "", prepareError(err)
+	}
+	return chatReq, sessionToken, nil
+}
+
+// routeError converts a routing failure for the requested modelID to a gRPC status
+func routeError(modelID string, err error) error {
+	var notFound *service.ModelNotFoundError
+	if errors.As(err, &notFound) {
+		return status.Errorf(codes.NotFound, "the model '%s' does not exist or is not available", modelID)
+	}
+	return status.Errorf(codes.Internal, "failed to validate model: %v", err)
+}
+
+// prepareError converts a failure to prepare a request to a gRPC status
+func prepareError(err error) error {
+	var lengthErr *service.ContextLengthError
+	if errors.As(err, &lengthErr) {
+		return status.Error(codes.InvalidArgument, lengthErr.Error())
+	}
+	return status.Errorf(codes.Unavailable, "%v", err)
+}
+
+// generationError converts a generation failure to a gRPC status
+func generationError(err error) error {
+	var upstream *service.UpstreamStatusError
+	switch {
+	case errors.Is(err, service.ErrInvalidModel):
+		return status.Error(codes.NotFound, "invalid model")
+	case errors.As(err, &upstream) && upstream.StatusCode == http.StatusTooManyRequests:
+		return status.Errorf(codes.ResourceExhausted, "generation failed: %v", err)
+	case errors.As(err, &upstream) && upstream.ServerError(),
+		errors.Is(err, context.DeadlineExceeded), errors.Is(err, service.ErrCircuitOpen):
+		return status.Errorf(codes.Unavailable, "generation failed: %v", err)
+	default:
+		return status.Errorf(codes.Internal, "generation failed: %v", err)
+	}
+}
+
+// toModel describes a catalog model, listed under id, as a protobuf model
+func toModel(id string, m model.Model) *akashchatv1.Model {
+	return &akashchatv1.Model{
+		Id:           id,
+		Name:         m.Name,
+		Description:  m.Description,
+		Available:    m.Available,
+		TokenLimit:   int32(m.TokenLimit),
+		Parameters:   m.Parameters,
+		Architecture: m.Architecture,
+		Capabilities: service.ModelCapabilities(m),
+	}
+}
+
+// toUsage converts token usage to its protobuf form
+func toUsage(u model.Usage) *akashchatv1.Usage {
+	return &akashchatv1.Usage{
+		PromptTokens:     int32(u.PromptTokens),
+		CompletionTokens: int32(u.CompletionTokens),
+		TotalTokens:      int32(u.TotalTokens),
+	}
+}
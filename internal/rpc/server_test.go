@@ -0,0 +1,303 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/auth"
+	"github.com/006lp/akashchat-api-go/internal/config"
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/006lp/akashchat-api-go/internal/service"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+	akashchatv1 "github.com/006lp/akashchat-api-go/pkg/pb/akashchat/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newUpstream starts a fake Akash Chat API. Chat requests for AkashGen start an
+// image job that succeeds on the first poll; other models reply "Hello world".
+func newUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/session/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session_token=test-session; Path=/")
+	})
+	mux.HandleFunc("/api/models/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]akash.Model{
+			{ID: "Meta-Llama-3-3-70B-Instruct", Name: "Llama 3.3 70B", TokenLimit: 128000, Available: true},
+			{ID: "AkashGen", Name: "AkashGen", Available: true},
+		})
+	})
+	mux.HandleFunc("/api/chat/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model == "AkashGen" {
+			fmt.Fprint(w, "Image generation started: jobId='job-1' prompt='a cat'")
+			return
+		}
+		w.Write([]byte("f:{\"messageId\":\"msg-test\"}\n0:\"Hello\"\n0:\" world\"\ne:{\"finishReason\":\"stop\"}\n"))
+	})
+	mux.HandleFunc("/api/image-status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"job_id":%q,"status":"succeeded","result":"/api/image/job-1.webp"}]`, r.URL.Query().Get("ids"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// syncBuffer is a buffer that the server and the test can use concurrently
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+// newTestConn serves the gRPC API over an in-memory listener, backed by a fake
+// upstream, and returns a connection to it together with the server's log
+func newTestConn(t *testing.T, apiKeys, publicURL string) (*grpc.ClientConn, *syncBuffer) {
+	t.Helper()
+
+	upstream := newUpstream(t)
+	client := akash.New(akash.WithBaseURL(upstream.URL))
+	sessionService := service.NewSessionService(client)
+	catalogService := service.NewCatalogService(client, 0)
+	akashService := service.NewAkashService(client, catalogService, nil)
+	aliasService := service.NewAliasService(&config.ModelConfig{})
+	contextService := service.NewContextService(catalogService, akashService, "truncate", "", 1024)
+	routerService := service.NewRouterService(sessionService, catalogService, aliasService, contextService)
+	imageService := service.NewImageService(client, 0, 0, publicURL, "")
+
+	keys, err := auth.ParseKeys(apiKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs := &syncBuffer{}
+	server := NewGRPCServer(NewServer(routerService, akashService, catalogService, aliasService, imageService), logging.New(logs, "info", "json", true), keys)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, logs
+}
+
+// chatRequest returns a request for a single user message
+func chatRequest() *akashchatv1.ChatCompletionRequest {
+	return &akashchatv1.ChatCompletionRequest{
+		Model:    "Meta-Llama-3-3-70B-Instruct",
+		Messages: []*akashchatv1.ChatMessage{{Role: "user", Content: "hi"}},
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	conn, logs := newTestConn(t, "ci:secret", "")
+	client := akashchatv1.NewAkashChatClient(conn)
+
+	_, err := client.ListModels(context.Background(), &akashchatv1.ListModelsRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ListModels() without a key: code = %v, want Unauthenticated", status.Code(err))
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	if _, err := client.ListModels(ctx, &akashchatv1.ListModelsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ListModels() with a wrong key: code = %v, want Unauthenticated", status.Code(err))
+	}
+
+	// Health checks stay public
+	if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("health check error = %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret", "x-request-id", "req-123")
+	var header metadata.MD
+	resp, err := client.ListModels(ctx, &akashchatv1.ListModelsRequest{}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("ListModels() with a key error = %v", err)
+	}
+	if len(resp.Models) != 2 {
+		t.Errorf("ListModels() returned %d models, want 2", len(resp.Models))
+	}
+	if got := header.Get("x-request-id"); len(got) != 1 || got[0] != "req-123" {
+		t.Errorf("x-request-id header = %v, want req-123", got)
+	}
+
+	var entry map[string]any
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["request_id"] != "req-123" || entry["key_name"] != "ci" || entry["code"] != "OK" {
+		t.Errorf("log entry = %v, want request_id req-123, key_name ci and code OK", entry)
+	}
+}
+
+func TestRequestIDPerCall(t *testing.T) {
+	conn, logs := newTestConn(t, "", "")
+	client := akashchatv1.NewAkashChatClient(conn)
+
+	var header metadata.MD
+	stream, err := client.StreamChatCompletion(context.Background(), chatRequest(), grpc.Header(&header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	requestID := header.Get("x-request-id")
+	if len(requestID) != 1 || requestID[0] == "" {
+		t.Fatalf("x-request-id header = %v, want a generated ID", requestID)
+	}
+	// The call is logged once, under the ID returned to the client
+	calls := 0
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry["msg"] == "rpc" {
+			calls++
+			if entry["request_id"] != requestID[0] {
+				t.Errorf("rpc log entry request_id = %v, want %s", entry["request_id"], requestID[0])
+			}
+		}
+	}
+	if calls != 1 {
+		t.Errorf("logged %d rpc entries, want 1", calls)
+	}
+}
+
+func TestChatCompletion(t *testing.T) {
+	conn, _ := newTestConn(t, "", "")
+	client := akashchatv1.NewAkashChatClient(conn)
+
+	resp, err := client.ChatCompletion(context.Background(), chatRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "Hello world" || resp.FinishReason != "stop" || resp.Id != "chatcmpl-msg-test" {
+		t.Errorf("ChatCompletion() = %+v", resp)
+	}
+	if resp.Usage == nil || resp.Usage.TotalTokens == 0 {
+		t.Errorf("ChatCompletion() usage = %v, want token counts", resp.Usage)
+	}
+
+	_, err = client.ChatCompletion(context.Background(), &akashchatv1.ChatCompletionRequest{Model: "missing", Messages: chatRequest().Messages})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ChatCompletion() with an unknown model: code = %v, want NotFound", status.Code(err))
+	}
+}
+
+func TestStreamChatCompletion(t *testing.T) {
+	conn, _ := newTestConn(t, "", "")
+	client := akashchatv1.NewAkashChatClient(conn)
+
+	stream, err := client.StreamChatCompletion(context.Background(), chatRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var content strings.Builder
+	var last *akashchatv1.ChatCompletionChunk
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chunk.Id != "chatcmpl-msg-test" {
+			t.Errorf("chunk id = %q", chunk.Id)
+		}
+		content.WriteString(chunk.Content)
+		last = chunk
+	}
+
+	if content.String() != "Hello world" {
+		t.Errorf("streamed content = %q, want %q", content.String(), "Hello world")
+	}
+	if last == nil || last.FinishReason != "stop" || last.Usage == nil {
+		t.Errorf("last chunk = %+v, want a finish reason and usage", last)
+	}
+}
+
+func TestGenerateImage(t *testing.T) {
+	tests := []struct {
+		name      string
+		publicURL string
+		wantURL   func(upstream string) bool
+	}{
+		{"proxy URL", "https://images.example.com", func(url string) bool {
+			return url == "https://images.example.com/v1/images/job-1.webp"
+		}},
+		{"Akash URL without a public URL", "", func(url string) bool {
+			return strings.HasPrefix(url, "http://127.0.0.1:") && strings.HasSuffix(url, "/api/image/job-1.webp")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, _ := newTestConn(t, "", tt.publicURL)
+			client := akashchatv1.NewAkashChatClient(conn)
+
+			resp, err := client.GenerateImage(context.Background(), &akashchatv1.GenerateImageRequest{Prompt: "a cat"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Images) != 1 {
+				t.Fatalf("GenerateImage() returned %d images, want 1", len(resp.Images))
+			}
+			if image := resp.Images[0]; image.JobId != "job-1" || !tt.wantURL(image.Url) {
+				t.Errorf("image = %+v", image)
+			}
+		})
+	}
+
+	conn, _ := newTestConn(t, "", "")
+	_, err := akashchatv1.NewAkashChatClient(conn).GenerateImage(context.Background(), &akashchatv1.GenerateImageRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GenerateImage() without a prompt: code = %v, want InvalidArgument", status.Code(err))
+	}
+}
@@ -1,402 +1,181 @@
 package service
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"regexp"
-	"strings"
+	"context"
+	"errors"
+	"net"
 	"time"
 
 	"github.com/006lp/akashchat-api-go/internal/model"
-	"github.com/006lp/akashchat-api-go/internal/utils"
-	"github.com/006lp/akashchat-api-go/pkg/client"
+	"github.com/006lp/akashchat-api-go/internal/tokenizer"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
 )
 
 // AkashService handles communication with Akash API
 type AkashService struct {
-	httpClient *client.HTTPClient
+	client         *akash.Client
+	catalogService *CatalogService
+	breaker        *CircuitBreaker
 }
 
-// NewAkashService creates a new AkashService instance
-func NewAkashService() *AkashService {
-	return &AkashService{
-		httpClient: client.NewHTTPClient(),
-	}
+// NewAkashService creates a new AkashService instance. The catalog selects the
+// tokenizer used to count tokens for each model, and breaker stops generation
+// requests while Akash is failing.
+func NewAkashService(client *akash.Client, catalogService *CatalogService, breaker *CircuitBreaker) *AkashService {
+	return &AkashService{client: client, catalogService: catalogService, breaker: breaker}
 }
 
-// ProcessImageGeneration handles image generation requests
-func (a *AkashService) ProcessImageGeneration(req model.ChatCompletionRequest, sessionToken string, temperature, topP float64) (*model.ImageGenerationData, error) {
-	// Create Akash chat request
-	akashReq := model.AkashChatRequest{
-		ID:          utils.GenerateRandomID(16),
-		Messages:    req.Messages,
-		Model:       req.Model,
-		System:      getSystemPrompt(),
-		Temperature: temperature,
-		TopP:        topP,
-		Context:     []interface{}{},
-	}
-
-	// Send chat request
-	respText, err := a.sendChatRequest(akashReq, sessionToken)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check for error response
-	if strings.Contains(respText, "error") && strings.Contains(respText, "Invalid model name") {
-		return nil, fmt.Errorf("invalid model")
-	}
-
-	// Extract jobId and prompt from response
-	jobID, prompt, err := a.extractImageGenerationInfo(respText)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract image generation info: %w", err)
-	}
-
-	// Poll for image completion
-	imageURL, err := a.pollImageStatus(jobID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get image result: %w", err)
-	}
-
-	return &model.ImageGenerationData{
-		Model:  req.Model,
-		JobID:  jobID,
-		Prompt: prompt,
-		Pic:    imageURL,
-	}, nil
+// Tokenizer returns the tokenizer for the model with the given ID
+func (a *AkashService) Tokenizer(ctx context.Context, modelID string) tokenizer.Tokenizer {
+	return a.catalogService.Tokenizer(ctx, modelID)
 }
 
-// ProcessTextGeneration handles text generation requests
-func (a *AkashService) ProcessTextGeneration(req model.ChatCompletionRequest, sessionToken string, temperature, topP float64) (*model.OpenAIChatCompletion, error) {
-	// Create Akash chat request
-	akashReq := model.AkashChatRequest{
-		ID:          utils.GenerateRandomID(16),
-		Messages:    req.Messages,
-		Model:       req.Model,
-		System:      getSystemPrompt(),
-		Temperature: temperature,
-		TopP:        topP,
-		Context:     []interface{}{},
-	}
-
-	// Send chat request
-	respText, err := a.sendChatRequest(akashReq, sessionToken)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check for error response
-	if strings.Contains(respText, "error") && strings.Contains(respText, "Invalid model name") {
-		return nil, fmt.Errorf("invalid model")
+// ErrInvalidModel is returned when Akash rejects the requested model name
+var ErrInvalidModel = akash.ErrInvalidModel
+
+// UpstreamStatusError is returned when Akash responds with a status other than 2xx
+type UpstreamStatusError = akash.StatusError
+
+// IsRetryable reports whether err may succeed on another model: Akash rejected
+// the model, returned a server error or timed out
+func IsRetryable(err error) bool {
+	var statusErr *UpstreamStatusError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrInvalidModel), errors.Is(err, context.DeadlineExceeded):
+		return true
+	case errors.As(err, &statusErr):
+		return statusErr.ServerError()
+	case errors.As(err, &netErr):
+		return netErr.Timeout()
+	default:
+		return false
 	}
-
-	// Extract and format the response
-	openAIResp := a.extractTextGenerationInfo(respText, req.Model)
-
-	return openAIResp, nil
 }
 
-// ProcessTextGenerationStream handles text generation requests with streaming
-func (a *AkashService) ProcessTextGenerationStream(req model.ChatCompletionRequest, sessionToken string, temperature, topP float64, writer io.Writer) error {
-	akashReq := model.AkashChatRequest{
-		ID:          utils.GenerateRandomID(16),
-		Messages:    req.Messages,
-		Model:       req.Model,
-		System:      getSystemPrompt(),
-		Temperature: temperature,
-		TopP:        topP,
-		Context:     []interface{}{},
+// ValidateImageOptions checks structured image parameters before they are sent upstream
+func ValidateImageOptions(opts *model.ImageOptions) error {
+	if opts == nil {
+		return nil
 	}
-
-	respBody, err := a.sendStreamChatRequest(akashReq, sessionToken)
-	if err != nil {
-		return err
-	}
-	defer respBody.Close()
-
-	// Process the stream
-	return a.processStream(respBody, req.Model, writer)
+	return opts.Validate()
 }
 
-func (a *AkashService) processStream(body io.Reader, modelName string, writer io.Writer) error {
-	scanner := bufio.NewScanner(body)
-	var messageID string
-	var contentStarted bool
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimSpace(line)
-
-		if strings.HasPrefix(line, "f:{\"messageId\":") {
-			msgIDRegex := regexp.MustCompile(`"messageId":"([^"]+)"`)
-			match := msgIDRegex.FindStringSubmatch(line)
-			if len(match) > 1 {
-				messageID = "chatcmpl-" + match[1]
-			}
-			contentStarted = true
-
-			// Send initial stream message
-			streamResp := model.OpenAIStreamCompletion{
-				ID:      messageID,
-				Object:  "chat.completion.chunk",
-				Created: time.Now().Unix(),
-				Model:   modelName,
-				Choices: []model.OpenAIStreamChoice{
-					{
-						Index: 0,
-						Delta: model.Delta{
-							Role: "assistant",
-						},
-					},
-				},
-			}
-			a.writeStreamResponse(writer, streamResp)
-			continue
-		}
-
-		if strings.HasPrefix(line, "e:{\"finishReason\":") {
-			reasonRegex := regexp.MustCompile(`"finishReason":"([^"]+)"`)
-			match := reasonRegex.FindStringSubmatch(line)
-			var finishReason string
-			if len(match) > 1 {
-				finishReason = match[1]
-			}
-
-			// Send final stream message
-			streamResp := model.OpenAIStreamCompletion{
-				ID:      messageID,
-				Object:  "chat.completion.chunk",
-				Created: time.Now().Unix(),
-				Model:   modelName,
-				Choices: []model.OpenAIStreamChoice{
-					{
-						Index:        0,
-						Delta:        model.Delta{},
-						FinishReason: finishReason,
-					},
-				},
-			}
-			a.writeStreamResponse(writer, streamResp)
-			break
-		}
-
-		if contentStarted && strings.HasPrefix(line, "0:\"") {
-			content := line[3:]
-			content = strings.TrimSuffix(content, "\"")
-			content = strings.ReplaceAll(content, "\\n", "\n")
-			content = strings.ReplaceAll(content, "\\\"", "\"")
-
-			streamResp := model.OpenAIStreamCompletion{
-				ID:      messageID,
-				Object:  "chat.completion.chunk",
-				Created: time.Now().Unix(),
-				Model:   modelName,
-				Choices: []model.OpenAIStreamChoice{
-					{
-						Index: 0,
-						Delta: model.Delta{
-							Content: content,
-						},
-					},
-				},
-			}
-			a.writeStreamResponse(writer, streamResp)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading stream: %w", err)
-	}
-
-	return nil
+// ProcessImageGenerations generates the number of images requested in req.Image concurrently
+func (a *AkashService) ProcessImageGenerations(ctx context.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64) ([]*model.ImageGenerationData, error) {
+	return a.generateImages(ctx, req, req.Image, sessionToken, temperature, topP)
 }
 
-func (a *AkashService) writeStreamResponse(writer io.Writer, resp model.OpenAIStreamCompletion) {
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	enc.SetEscapeHTML(false)
-	enc.Encode(resp)
-
-	fmt.Fprintf(writer, "data: %s\n\n", buf.String())
-	if flusher, ok := writer.(http.Flusher); ok {
-		flusher.Flush()
-	}
-}
-
-// sendStreamChatRequest sends a request to Akash chat API and returns the response body as a stream
-func (a *AkashService) sendStreamChatRequest(req model.AkashChatRequest, sessionToken string) (io.ReadCloser, error) {
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	headers := map[string]string{
-		"Referer":      "https://chat.akash.network/",
-		"Cookie":       sessionToken,
-		"Accept":       "*/*",
-		"Content-Type": "application/json",
+// ProcessImageGeneration handles image generation requests
+func (a *AkashService) ProcessImageGeneration(ctx context.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64) (*model.ImageGenerationData, error) {
+	// A single request is sent regardless of the requested image count
+	var opts *model.ImageOptions
+	if req.Image != nil {
+		single := *req.Image
+		single.N = 0
+		opts = &single
 	}
 
-	resp, err := a.httpClient.Post("https://chat.akash.network/api/chat/", bytes.NewBuffer(jsonData), headers)
+	images, err := a.generateImages(ctx, req, opts, sessionToken, temperature, topP)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send chat request: %w", err)
+		return nil, err
 	}
 
-	return resp.Body, nil
+	return images[0], nil
 }
 
-// sendChatRequest sends a request to Akash chat API
-func (a *AkashService) sendChatRequest(req model.AkashChatRequest, sessionToken string) (string, error) {
-	respBody, err := a.sendStreamChatRequest(req, sessionToken)
-	if err != nil {
-		return "", err
+// generateImages runs an image generation and converts the results to the API format
+func (a *AkashService) generateImages(ctx context.Context, req model.ChatCompletionRequest, opts *model.ImageOptions, sessionToken string, temperature, topP float64) ([]*model.ImageGenerationData, error) {
+	if err := a.breaker.Allow(); err != nil {
+		return nil, err
 	}
-	defer respBody.Close()
-
-	bodyBytes, err := io.ReadAll(respBody)
+	results, err := a.client.GenerateImage(ctx, akash.ImageRequest{
+		Model:        req.Model,
+		Messages:     toMessages(req.Messages),
+		Options:      opts,
+		SystemPrompt: systemPrompt(req),
+		Temperature:  &temperature,
+		TopP:         &topP,
+		Session:      sessionToken,
+	})
+	a.breaker.Record(err)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	return string(bodyBytes), nil
-}
-
-// extractImageGenerationInfo extracts jobId and prompt from image generation response
-func (a *AkashService) extractImageGenerationInfo(respText string) (string, string, error) {
-	// Extract jobId using regex
-	jobIDRegex := regexp.MustCompile(`jobId='([^']+)'`)
-	jobIDMatch := jobIDRegex.FindStringSubmatch(respText)
-	if len(jobIDMatch) < 2 {
-		return "", "", fmt.Errorf("jobId not found in response")
+		return nil, err
 	}
-	jobID := jobIDMatch[1]
 
-	// Extract prompt using regex
-	promptRegex := regexp.MustCompile(`prompt='([^']+)'`)
-	promptMatch := promptRegex.FindStringSubmatch(respText)
-	if len(promptMatch) < 2 {
-		return "", "", fmt.Errorf("prompt not found in response")
+	images := make([]*model.ImageGenerationData, 0, len(results))
+	for _, image := range results {
+		images = append(images, &model.ImageGenerationData{
+			Model:          image.Model,
+			JobID:          image.JobID,
+			Prompt:         image.Prompt,
+			OriginalPrompt: image.OriginalPrompt,
+			Pic:            image.URL,
+		})
 	}
-	prompt := promptMatch[1]
 
-	return jobID, prompt, nil
+	return images, nil
 }
 
-// pollImageStatus polls the image status until completion
-func (a *AkashService) pollImageStatus(jobID string) (string, error) {
-	maxAttempts := 60 // Maximum 1 minute polling
-	
-	for i := 0; i < maxAttempts; i++ {
-		url := fmt.Sprintf("https://chat.akash.network/api/image-status?ids=%s", jobID)
-		
-		resp, err := a.httpClient.Get(url, nil)
-		if err != nil {
-			return "", fmt.Errorf("failed to check image status: %w", err)
-		}
-
-		var statusResp []model.ImageStatusResponse
-		if err := json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
-			resp.Body.Close()
-			return "", fmt.Errorf("failed to decode image status response: %w", err)
-		}
-		resp.Body.Close()
-
-		if len(statusResp) == 0 {
-			return "", fmt.Errorf("empty status response")
-		}
-
-		status := statusResp[0]
-		if status.Status == "succeeded" {
-			return "https://chat.akash.network" + status.Result, nil
-		}
-
-		if status.Status == "failed" {
-			return "", fmt.Errorf("image generation failed")
-		}
-
-		// Wait 1 second before next poll
-		time.Sleep(1 * time.Second)
+// ProcessTextGeneration handles text generation requests
+func (a *AkashService) ProcessTextGeneration(ctx context.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64) (*model.OpenAIChatCompletion, error) {
+	if err := a.breaker.Allow(); err != nil {
+		return nil, err
 	}
-
-	return "", fmt.Errorf("image generation timed out")
-}
-
-// extractTextGenerationInfo extracts text generation information from response and formats it as OpenAI's chat completion.
-func (a *AkashService) extractTextGenerationInfo(respText string, modelName string) *model.OpenAIChatCompletion {
-	var messageID string
-	var allContent strings.Builder
-	var finishReason string
-
-	lines := strings.Split(respText, "\n")
-	var contentStarted bool
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Extract messageId
-		if strings.HasPrefix(line, "f:{\"messageId\":") {
-			msgIDRegex := regexp.MustCompile(`"messageId":"([^"]+)"`)
-			match := msgIDRegex.FindStringSubmatch(line)
-			if len(match) > 1 {
-				messageID = match[1]
-			}
-			contentStarted = true
-			continue
-		}
-
-		// Extract finishReason
-		if strings.HasPrefix(line, "e:{\"finishReason\":") {
-			reasonRegex := regexp.MustCompile(`"finishReason":"([^"]+)"`)
-			match := reasonRegex.FindStringSubmatch(line)
-			if len(match) > 1 {
-				finishReason = match[1]
-			}
-			break
-		}
-
-		// Collect content lines
-		if contentStarted && strings.HasPrefix(line, "0:\"") {
-			content := line[3:]
-			content = strings.TrimSuffix(content, "\"")
-			content = strings.ReplaceAll(content, "\\n", "\n")
-			content = strings.ReplaceAll(content, "\\\"", "\"")
-			allContent.WriteString(content)
-		}
+	resp, err := a.client.Chat(ctx, chatRequest(req, sessionToken, temperature, topP))
+	a.breaker.Record(err)
+	if err != nil {
+		return nil, err
 	}
 
-	fullContent := allContent.String()
+	tok := a.Tokenizer(ctx, req.Model)
+	content, finishReason, _ := applyLimits(tok, req, resp.Content, resp.FinishReason)
 
 	// Create OpenAI format response
 	return &model.OpenAIChatCompletion{
-		ID:      "chatcmpl-" + messageID,
+		ID:      "chatcmpl-" + resp.MessageID,
 		Object:  "chat.completion",
 		Created: time.Now().Unix(),
-		Model:   modelName,
+		Model:   req.Model,
 		Choices: []model.Choice{
 			{
 				Index: 0,
 				Message: model.Message{
 					Role:    "assistant",
-					Content: fullContent,
+					Content: content,
 				},
 				FinishReason: finishReason,
 			},
 		},
-		Usage: model.Usage{
-			PromptTokens:     0, // Placeholder, as Akash does not provide this
-			CompletionTokens: 0, // Placeholder
-			TotalTokens:      0, // Placeholder
-		},
+		Usage: countUsage(tok, req, content),
+	}, nil
+}
+
+// chatRequest converts an API request to an Akash chat request
+func chatRequest(req model.ChatCompletionRequest, sessionToken string, temperature, topP float64) akash.ChatRequest {
+	return akash.ChatRequest{
+		Model:        req.Model,
+		Messages:     toMessages(req.Messages),
+		SystemPrompt: systemPrompt(req),
+		Temperature:  &temperature,
+		TopP:         &topP,
+		Session:      sessionToken,
 	}
 }
 
-// getSystemPrompt returns the system prompt for Akash
-func getSystemPrompt() string {
-	return "You are a skilled conversationalist who adapts naturally to what users need. Your responses match the situation—whether someone wants deep analysis, casual chat, emotional support, creative collaboration, or just needs to vent.\nCore Approach\n\nRead between the lines to understand what people actually want\nMatch their energy and conversational style\nShift seamlessly between modes: analytical, empathetic, humorous, creative, or practical\nWhen people need to be heard, focus on listening rather than fixing\nFor substantive topics, provide thorough, well-organized insights that aid decision-making\n\nCommunication Style\n\nSound natural and authentic, never templated or robotic\nAvoid unnecessary politeness policing or inclusion reminders\nWrite in requested voices, styles, or perspectives when asked\nAdapt tone appropriately—you can be direct, irreverent, or even rude when specifically prompted to do so\n\nInteraction Philosophy\n\nSometimes the best help is simply being present and understanding\nDon't over-optimize for helpfulness when someone just wants connection\nTrust that users know what they're looking for and deliver accordingly\nProvide depth and insight for complex topics while keeping casual conversations light"
-}
\ No newline at end of file
+// toMessages converts API chat messages to Akash messages
+func toMessages(messages []model.ChatMessage) []akash.Message {
+	result := make([]akash.Message, len(messages))
+	for i, msg := range messages {
+		result[i] = akash.Message{Role: msg.Role, Content: msg.Content}
+	}
+	return result
+}
+
+// systemPrompt returns the request's system prompt override or the default Akash prompt
+func systemPrompt(req model.ChatCompletionRequest) string {
+	if req.SystemPrompt != "" {
+		return req.SystemPrompt
+	}
+	return akash.DefaultSystemPrompt
+}
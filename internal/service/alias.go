@@ -0,0 +1,138 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/006lp/akashchat-api-go/internal/config"
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+// AliasService resolves model aliases and virtual models to real Akash models
+// and holds the fallback chain configured for each model
+type AliasService struct {
+	aliases   map[string]string
+	virtual   map[string]config.VirtualModel
+	fallbacks map[string][]string
+}
+
+// ModelAlias describes a configured alias or virtual model
+type ModelAlias struct {
+	ID          string
+	Target      string
+	Description string
+	Virtual     bool
+}
+
+// NewAliasService creates a new AliasService instance from a validated model config
+func NewAliasService(cfg *config.ModelConfig) *AliasService {
+	s := &AliasService{
+		aliases:   make(map[string]string),
+		virtual:   make(map[string]config.VirtualModel),
+		fallbacks: make(map[string][]string),
+	}
+
+	if cfg == nil {
+		return s
+	}
+	for name, target := range cfg.Aliases {
+		s.aliases[name] = target
+	}
+	for _, vm := range cfg.VirtualModels {
+		s.virtual[vm.ID] = vm
+	}
+
+	// Key fallback chains by real model so aliases share their target's chain
+	for name, chain := range cfg.Fallbacks {
+		target := s.Target(name)
+		for _, fallback := range chain {
+			s.fallbacks[target] = append(s.fallbacks[target], s.Target(fallback))
+		}
+	}
+	return s
+}
+
+// Resolve rewrites req.Model to the real model behind any alias or virtual model.
+// Virtual model presets only fill values the request does not set itself; when
+// virtual models are chained the outermost one wins. It reports whether req.Model changed.
+func (s *AliasService) Resolve(req *model.ChatCompletionRequest) bool {
+	requested := req.Model
+
+	for {
+		if vm, ok := s.virtual[req.Model]; ok {
+			if req.SystemPrompt == "" {
+				req.SystemPrompt = vm.SystemPrompt
+			}
+			if req.Temperature == nil && vm.Temperature != nil {
+				temperature := *vm.Temperature
+				req.Temperature = &temperature
+			}
+			if req.TopP == nil && vm.TopP != nil {
+				topP := *vm.TopP
+				req.TopP = &topP
+			}
+			req.Model = vm.Model
+			continue
+		}
+
+		if target, ok := s.aliases[req.Model]; ok {
+			req.Model = target
+			continue
+		}
+
+		return req.Model != requested
+	}
+}
+
+// Target returns the real model behind name, or name itself if it is not an alias
+func (s *AliasService) Target(name string) string {
+	req := model.ChatCompletionRequest{Model: name}
+	s.Resolve(&req)
+	return req.Model
+}
+
+// Aliases returns every configured alias and virtual model sorted by ID
+func (s *AliasService) Aliases() []ModelAlias {
+	aliases := make([]ModelAlias, 0, len(s.aliases)+len(s.virtual))
+	for name := range s.aliases {
+		aliases = append(aliases, ModelAlias{ID: name, Target: s.Target(name)})
+	}
+	for name, vm := range s.virtual {
+		aliases = append(aliases, ModelAlias{
+			ID:          name,
+			Target:      s.Target(name),
+			Description: vm.Description,
+			Virtual:     true,
+		})
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].ID < aliases[j].ID
+	})
+	return aliases
+}
+
+// Lookup returns the alias or virtual model named name
+func (s *AliasService) Lookup(name string) (ModelAlias, bool) {
+	if _, ok := s.aliases[name]; ok {
+		return ModelAlias{ID: name, Target: s.Target(name)}, true
+	}
+	if vm, ok := s.virtual[name]; ok {
+		return ModelAlias{ID: name, Target: s.Target(name), Description: vm.Description, Virtual: true}, true
+	}
+	return ModelAlias{}, false
+}
+
+// Fallbacks returns the real models to try, in order, when name fails
+func (s *AliasService) Fallbacks(name string) []string {
+	target := s.Target(name)
+	seen := map[string]bool{target: true}
+
+	var fallbacks []string
+	for _, fallback := range s.fallbacks[target] {
+		if !seen[fallback] {
+			seen[fallback] = true
+			fallbacks = append(fallbacks, fallback)
+		}
+	}
+	return fallbacks
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/config"
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+// floatPtr returns a pointer to v
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// testAliasService configures an alias, a virtual model on top of it and a
+// virtual model wrapping the first one
+func testAliasService() *AliasService {
+	return NewAliasService(&config.ModelConfig{
+		Aliases: map[string]string{
+			"fast":   "Meta-Llama-3-3-70B-Instruct",
+			"faster": "fast",
+		},
+		VirtualModels: []config.VirtualModel{
+			{ID: "coder", Model: "fast", SystemPrompt: "You write code.", Temperature: floatPtr(0.2), TopP: floatPtr(0.9)},
+			{ID: "strict-coder", Model: "coder", Temperature: floatPtr(0)},
+		},
+		Fallbacks: map[string][]string{
+			"faster": {"Qwen3-235B-A22B-Instruct-2507-FP8", "fast", "Qwen3-235B-A22B-Instruct-2507-FP8"},
+		},
+	})
+}
+
+func TestAliasServiceResolve(t *testing.T) {
+	tests := []struct {
+		name            string
+		req             model.ChatCompletionRequest
+		wantModel       string
+		wantChanged     bool
+		wantSystem      string
+		wantTemperature *float64
+		wantTopP        *float64
+	}{
+		{
+			name:      "real model",
+			req:       model.ChatCompletionRequest{Model: "Meta-Llama-3-3-70B-Instruct"},
+			wantModel: "Meta-Llama-3-3-70B-Instruct",
+		},
+		{
+			name:      "unknown model",
+			req:       model.ChatCompletionRequest{Model: "gpt-4"},
+			wantModel: "gpt-4",
+		},
+		{
+			name:        "chained aliases",
+			req:         model.ChatCompletionRequest{Model: "faster"},
+			wantModel:   "Meta-Llama-3-3-70B-Instruct",
+			wantChanged: true,
+		},
+		{
+			name:            "virtual model presets",
+			req:             model.ChatCompletionRequest{Model: "coder"},
+			wantModel:       "Meta-Llama-3-3-70B-Instruct",
+			wantChanged:     true,
+			wantSystem:      "You write code.",
+			wantTemperature: floatPtr(0.2),
+			wantTopP:        floatPtr(0.9),
+		},
+		{
+			name:            "caller values override presets",
+			req:             model.ChatCompletionRequest{Model: "coder", Temperature: floatPtr(1.1)},
+			wantModel:       "Meta-Llama-3-3-70B-Instruct",
+			wantChanged:     true,
+			wantSystem:      "You write code.",
+			wantTemperature: floatPtr(1.1),
+			wantTopP:        floatPtr(0.9),
+		},
+		{
+			name:            "outer virtual model wins and inner presets fill the rest",
+			req:             model.ChatCompletionRequest{Model: "strict-coder"},
+			wantModel:       "Meta-Llama-3-3-70B-Instruct",
+			wantChanged:     true,
+			wantSystem:      "You write code.",
+			wantTemperature: floatPtr(0),
+			wantTopP:        floatPtr(0.9),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			changed := testAliasService().Resolve(&req)
+
+			if req.Model != tt.wantModel || changed != tt.wantChanged {
+				t.Errorf("Resolve() = %v with model %q, want %v with %q", changed, req.Model, tt.wantChanged, tt.wantModel)
+			}
+			if req.SystemPrompt != tt.wantSystem {
+				t.Errorf("system prompt = %q, want %q", req.SystemPrompt, tt.wantSystem)
+			}
+			if !reflect.DeepEqual(req.Temperature, tt.wantTemperature) {
+				t.Errorf("temperature = %v, want %v", deref(req.Temperature), deref(tt.wantTemperature))
+			}
+			if !reflect.DeepEqual(req.TopP, tt.wantTopP) {
+				t.Errorf("top_p = %v, want %v", deref(req.TopP), deref(tt.wantTopP))
+			}
+		})
+	}
+}
+
+func TestAliasServiceResolveCopiesPresets(t *testing.T) {
+	s := testAliasService()
+
+	req := model.ChatCompletionRequest{Model: "coder"}
+	s.Resolve(&req)
+	*req.Temperature = 2
+
+	// Changing one request must not change the preset for the next
+	next := model.ChatCompletionRequest{Model: "coder"}
+	s.Resolve(&next)
+	if *next.Temperature != 0.2 {
+		t.Errorf("temperature = %v after an earlier request changed it, want 0.2", *next.Temperature)
+	}
+}
+
+func TestAliasServiceFallbacks(t *testing.T) {
+	s := testAliasService()
+
+	// Chains are keyed by the real model, deduplicated and never include the model itself
+	want := []string{"Qwen3-235B-A22B-Instruct-2507-FP8"}
+	for _, name := range []string{"faster", "fast", "coder", "Meta-Llama-3-3-70B-Instruct"} {
+		if got := s.Fallbacks(name); !reflect.DeepEqual(got, want) {
+			t.Errorf("Fallbacks(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if got := s.Fallbacks("Qwen3-235B-A22B-Instruct-2507-FP8"); len(got) != 0 {
+		t.Errorf("Fallbacks() of a model without a chain = %v", got)
+	}
+}
+
+func TestAliasServiceLookup(t *testing.T) {
+	s := testAliasService()
+
+	alias, ok := s.Lookup("strict-coder")
+	if !ok || !alias.Virtual || alias.Target != "Meta-Llama-3-3-70B-Instruct" {
+		t.Errorf("Lookup(strict-coder) = %+v, %v", alias, ok)
+	}
+	if _, ok := s.Lookup("Meta-Llama-3-3-70B-Instruct"); ok {
+		t.Error("Lookup() found a real model")
+	}
+
+	var ids []string
+	for _, alias := range s.Aliases() {
+		ids = append(ids, alias.ID)
+	}
+	if want := []string{"coder", "fast", "faster", "strict-coder"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Aliases() = %v, want %v", ids, want)
+	}
+}
+
+// deref returns the value of v for messages, or nil
+func deref(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
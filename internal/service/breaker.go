@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// ErrCircuitOpen is returned without contacting Akash while the circuit breaker is open
+var ErrCircuitOpen = errors.New("akash upstream is failing, circuit breaker is open")
+
+// CircuitBreaker stops sending requests to Akash after a run of consecutive
+// upstream failures. Once the cooldown has passed a single trial request is let
+// through; it closes the circuit on success and reopens it on failure.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	trial     bool
+	mutex     sync.Mutex
+}
+
+// NewCircuitBreaker creates a breaker that opens after threshold consecutive
+// failures for cooldown. A threshold of zero or less disables it.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Allow returns ErrCircuitOpen when a request must not be sent upstream
+func (b *CircuitBreaker) Allow() error {
+	if b == nil || b.threshold <= 0 {
+		return nil
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state() {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+	}
+	return nil
+}
+
+// Record reports the outcome of an upstream request let through by Allow. Only
+// upstream failures count and only a success closes the circuit; errors caused
+// by the client or the request leave the state unchanged.
+func (b *CircuitBreaker) Record(err error) {
+	if b == nil || b.threshold <= 0 {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch {
+	case err == nil:
+		b.failures = 0
+		b.trial = false
+		return
+	case !upstreamFailure(err):
+		// The outcome says nothing about Akash; free the trial slot for the next request
+		b.trial = false
+		return
+	}
+
+	b.failures++
+	if b.trial || b.failures >= b.threshold {
+		b.openedAt = time.Now()
+		b.trial = false
+	}
+}
+
+// State returns the current breaker state
+func (b *CircuitBreaker) State() string {
+	if b == nil || b.threshold <= 0 {
+		return BreakerClosed
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state()
+}
+
+// state returns the current state; callers must hold mutex
+func (b *CircuitBreaker) state() string {
+	switch {
+	case b.failures < b.threshold && !b.trial:
+		return BreakerClosed
+	case time.Since(b.openedAt) < b.cooldown:
+		return BreakerOpen
+	default:
+		return BreakerHalfOpen
+	}
+}
+
+// upstreamFailure reports whether err means Akash is failing: it responded with
+// a server error, could not be reached or did not answer in time
+func upstreamFailure(err error) bool {
+	var statusErr *UpstreamStatusError
+	var netErr net.Error
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		return false
+	case errors.As(err, &statusErr):
+		return statusErr.ServerError()
+	case errors.Is(err, context.DeadlineExceeded):
+		return true
+	default:
+		return errors.As(err, &netErr)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	b := NewCircuitBreaker(2, 50*time.Millisecond)
+	upstreamErr := &UpstreamStatusError{StatusCode: 502}
+
+	// Errors caused by the client or the request do not count
+	b.Record(context.Canceled)
+	b.Record(ErrInvalidModel)
+	b.Record(&UpstreamStatusError{StatusCode: 429})
+	b.Record(upstreamErr)
+	if b.State() != BreakerClosed || b.Allow() != nil {
+		t.Fatalf("state = %s after one upstream failure, want closed", b.State())
+	}
+
+	b.Record(upstreamErr)
+	if b.State() != BreakerOpen || !errors.Is(b.Allow(), ErrCircuitOpen) {
+		t.Fatalf("state = %s after two upstream failures, want open", b.State())
+	}
+
+	// After the cooldown a single trial request is let through
+	time.Sleep(60 * time.Millisecond)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state = %s after the cooldown, want half_open", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("trial request rejected: %v", err)
+	}
+	if !errors.Is(b.Allow(), ErrCircuitOpen) {
+		t.Fatal("second request allowed while the trial is running")
+	}
+
+	// A failed trial reopens the circuit, a successful one closes it
+	b.Record(upstreamErr)
+	if b.State() != BreakerOpen {
+		t.Fatalf("state = %s after a failed trial, want open", b.State())
+	}
+	time.Sleep(60 * time.Millisecond)
+	b.Allow()
+	b.Record(nil)
+	if b.State() != BreakerClosed {
+		t.Fatalf("state = %s after a successful trial, want closed", b.State())
+	}
+}
+
+func TestCircuitBreakerTrialWithoutUpstreamResult(t *testing.T) {
+	b := NewCircuitBreaker(1, 50*time.Millisecond)
+	b.Record(&UpstreamStatusError{StatusCode: 502})
+	time.Sleep(60 * time.Millisecond)
+
+	// A cancelled or rejected trial neither closes nor reopens the circuit
+	for _, err := range []error{context.Canceled, ErrInvalidModel} {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("trial request rejected: %v", err)
+		}
+		b.Record(err)
+		if b.State() != BreakerHalfOpen {
+			t.Fatalf("state = %s after a trial ending with %v, want half_open", b.State(), err)
+		}
+	}
+
+	// The next trial decides
+	if err := b.Allow(); err != nil {
+		t.Fatalf("trial request rejected after a cancelled trial: %v", err)
+	}
+	b.Record(&UpstreamStatusError{StatusCode: 503})
+	if b.State() != BreakerOpen {
+		t.Fatalf("state = %s after a failed trial, want open", b.State())
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	for _, b := range []*CircuitBreaker{nil, NewCircuitBreaker(0, time.Minute)} {
+		for i := 0; i < 10; i++ {
+			b.Record(&UpstreamStatusError{StatusCode: 502})
+		}
+		if err := b.Allow(); err != nil || b.State() != BreakerClosed {
+			t.Errorf("disabled breaker: Allow() = %v, State() = %s", err, b.State())
+		}
+	}
+}
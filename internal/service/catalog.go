@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/metrics"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/tokenizer"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+)
+
+// coldLoadRetry is how long a failed fetch of a never loaded catalog is reported
+// to callers before another fetch is attempted
+const coldLoadRetry = 10 * time.Second
+
+// CatalogService caches the Akash model list and refreshes it in the background
+type CatalogService struct {
+	client    *akash.Client
+	interval  time.Duration
+	models    []model.Model
+	lastFetch time.Time
+	lastErr   error
+	lastErrAt time.Time
+	mutex     sync.RWMutex
+	refreshMu sync.Mutex
+}
+
+// NewCatalogService creates a new CatalogService instance
+func NewCatalogService(client *akash.Client, interval time.Duration) *CatalogService {
+	return &CatalogService{
+		client:   client,
+		interval: interval,
+	}
+}
+
+// Start fetches the catalog and keeps refreshing it until ctx is cancelled
+func (s *CatalogService) Start(ctx context.Context) {
+	go func() {
+		if err := s.Refresh(ctx); err != nil {
+			slog.Warn("initial model catalog fetch failed", "error", err)
+		}
+
+		if s.interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Refresh(ctx); err != nil {
+					slog.Warn("model catalog refresh failed, serving stale data", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Refresh fetches the model list from Akash, keeping the previous list on failure
+func (s *CatalogService) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	return s.fetch(ctx)
+}
+
+// fetch downloads and stores the model list; callers must hold refreshMu
+func (s *CatalogService) fetch(ctx context.Context) error {
+	models, err := s.client.Models(ctx)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err != nil {
+		s.lastErr, s.lastErrAt = err, time.Now()
+		return err
+	}
+	s.models = models
+	s.lastFetch = time.Now()
+	s.lastErr = nil
+
+	ids := make([]string, 0, len(models))
+	for _, m := range models {
+		ids = append(ids, m.ID)
+	}
+	metrics.SetModels(ids)
+	return nil
+}
+
+// Models returns the cached model list, fetching it first if the cache is empty.
+// After a failed first fetch the error is returned without contacting Akash until
+// coldLoadRetry has passed, so requests do not queue up behind a failing upstream.
+func (s *CatalogService) Models(ctx context.Context) ([]model.Model, error) {
+	if models := s.loaded(); models != nil {
+		return models, nil
+	}
+	if err := s.recentFailure(); err != nil {
+		return nil, err
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	// Another request may have loaded the catalog, or failed to, while we waited
+	if models := s.loaded(); models != nil {
+		return models, nil
+	}
+	if err := s.recentFailure(); err != nil {
+		return nil, err
+	}
+	if err := s.fetch(ctx); err != nil {
+		return nil, err
+	}
+	return s.loaded(), nil
+}
+
+// loaded returns the cached model list, or nil if it has never been fetched
+func (s *CatalogService) loaded() []model.Model {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.models
+}
+
+// recentFailure returns the error of the last fetch if it failed within coldLoadRetry
+func (s *CatalogService) recentFailure() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.lastErr != nil && time.Since(s.lastErrAt) < coldLoadRetry {
+		return fmt.Errorf("model catalog unavailable: %w", s.lastErr)
+	}
+	return nil
+}
+
+// Lookup returns the catalog entry for id
+func (s *CatalogService) Lookup(ctx context.Context, id string) (model.Model, bool, error) {
+	models, err := s.Models(ctx)
+	if err != nil {
+		return model.Model{}, false, err
+	}
+
+	for _, m := range models {
+		if m.ID == id {
+			return m, true, nil
+		}
+	}
+	return model.Model{}, false, nil
+}
+
+// Tokenizer returns the tokenizer for the model with the given ID, selected from
+// its catalog metadata when the model is known
+func (s *CatalogService) Tokenizer(ctx context.Context, id string) tokenizer.Tokenizer {
+	m, ok, err := s.Lookup(ctx, id)
+	if err != nil || !ok {
+		m = model.Model{ID: id}
+	}
+	return tokenizer.ForModel(m)
+}
+
+// Model capabilities derived from catalog metadata
+const (
+	CapabilityChat            = "chat"
+	CapabilityVision          = "vision"
+	CapabilityImageGeneration = "image_generation"
+	CapabilityReasoning       = "reasoning"
+)
+
+var (
+	visionHints    = []string{"vision", "-vl", "vl-", "llava", "multimodal", "image understanding"}
+	reasoningHints = []string{"deepseek-r1", "-r1-", "qwq", "reasoning", "thinking", "gpt-oss"}
+)
+
+// ModelCapabilities derives what a model can do from its ID, name and description
+func ModelCapabilities(m model.Model) []string {
+	text := strings.ToLower(m.ID + " " + m.Name + " " + m.Description)
+
+	if m.ID == "AkashGen" || strings.Contains(text, "image generation") {
+		return []string{CapabilityImageGeneration}
+	}
+
+	capabilities := []string{CapabilityChat}
+	if containsAny(text, visionHints) {
+		capabilities = append(capabilities, CapabilityVision)
+	}
+	if containsAny(text, reasoningHints) {
+		capabilities = append(capabilities, CapabilityReasoning)
+	}
+	return capabilities
+}
+
+// containsAny reports whether s contains any of substrs
+func containsAny(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxModelSuggestions is the number of close matches offered for an unknown model
+const maxModelSuggestions = 3
+
+// ModelNotFoundError is returned when a requested model is unknown or unavailable
+type ModelNotFoundError struct {
+	Model       string
+	Unavailable bool
+	Suggestions []string
+}
+
+func (e *ModelNotFoundError) Error() string {
+	if e.Unavailable {
+		return fmt.Sprintf("model %q is currently unavailable", e.Model)
+	}
+	return fmt.Sprintf("model %q does not exist", e.Model)
+}
+
+// Validate checks that id names an available catalog model. When the catalog
+// cannot be loaded the request is allowed through and upstream decides.
+func (s *CatalogService) Validate(ctx context.Context, id string) error {
+	models, err := s.Models(ctx)
+	if err != nil {
+		slog.Warn("skipping model validation, catalog unavailable", "error", err)
+		return nil
+	}
+
+	var available []string
+	notFound := &ModelNotFoundError{Model: id}
+	for _, m := range models {
+		if m.ID == id {
+			if m.Available {
+				return nil
+			}
+			notFound.Unavailable = true
+			continue
+		}
+		if m.Available {
+			available = append(available, m.ID)
+		}
+	}
+
+	notFound.Suggestions = closestModels(id, available, maxModelSuggestions)
+	return notFound
+}
+
+// Available returns the ids that Validate accepts, preserving order
+func (s *CatalogService) Available(ctx context.Context, ids []string) []string {
+	var available []string
+	for _, id := range ids {
+		if s.Validate(ctx, id) == nil {
+			available = append(available, id)
+		}
+	}
+	return available
+}
+
+// closestModels returns up to n candidates ranked by shared name tokens, then edit distance
+func closestModels(id string, candidates []string, n int) []string {
+	type scored struct {
+		id       string
+		shared   int
+		distance int
+	}
+
+	target := strings.ToLower(id)
+	targetTokens := modelTokens(target)
+
+	var ranked []scored
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		candidateTokens := modelTokens(lower)
+
+		shared := 0
+		for token := range targetTokens {
+			if candidateTokens[token] {
+				shared++
+			}
+		}
+
+		distance := levenshtein(target, lower)
+		if shared == 0 && distance > len(target)/3 {
+			continue
+		}
+		ranked = append(ranked, scored{id: candidate, shared: shared, distance: distance})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].shared != ranked[j].shared {
+			return ranked[i].shared > ranked[j].shared
+		}
+		return ranked[i].distance < ranked[j].distance
+	})
+
+	var result []string
+	for i := 0; i < len(ranked) && i < n; i++ {
+		result = append(result, ranked[i].id)
+	}
+	return result
+}
+
+// modelTokens splits a lowercase model name into its dash, underscore and dot separated parts
+func modelTokens(name string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, token := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' ' || r == '/'
+	}) {
+		tokens[token] = true
+	}
+	return tokens
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// LastSuccessfulFetch returns when the catalog was last fetched from Akash
+func (s *CatalogService) LastSuccessfulFetch() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.lastFetch
+}
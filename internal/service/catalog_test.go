@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+)
+
+func TestCatalogColdLoadFailureIsCached(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	s := NewCatalogService(akash.New(akash.WithBaseURL(server.URL)), 0)
+	for i := 0; i < 3; i++ {
+		if _, err := s.Models(context.Background()); err == nil {
+			t.Fatal("Models() succeeded against a failing upstream")
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("upstream fetched %d times, want 1 until coldLoadRetry has passed", n)
+	}
+
+	// Background refreshes are not delayed by the cached failure
+	if err := s.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() succeeded against a failing upstream")
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("upstream fetched %d times, want 2", n)
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/tokenizer"
+)
+
+// Context window strategies applied when a prompt exceeds the model's token limit
+const (
+	ContextReject    = "reject"
+	ContextTruncate  = "truncate"
+	ContextSummarize = "summarize"
+)
+
+// Context actions reported back to the client
+const (
+	ContextActionNone       = "none"
+	ContextActionTruncated  = "truncated"
+	ContextActionSummarized = "summarized"
+)
+
+// messageOverheadTokens approximates the formatting tokens added per message
+const messageOverheadTokens = 4
+
+// ContextLengthError is returned when a prompt does not fit the model's context window
+type ContextLengthError struct {
+	Model  string
+	Limit  int
+	Tokens int
+}
+
+func (e *ContextLengthError) Error() string {
+	return fmt.Sprintf("This model's maximum context length is %d tokens, but the request has about %d tokens.", e.Limit, e.Tokens)
+}
+
+// ContextResult describes what was done to fit a request into the context window
+type ContextResult struct {
+	Action  string
+	Dropped int
+	Tokens  int
+	Limit   int
+}
+
+// ContextService keeps prompts within the catalog token limit of their model
+type ContextService struct {
+	catalogService *CatalogService
+	akashService   *AkashService
+	strategy       string
+	summaryModel   string
+	reserveTokens  int
+}
+
+// NewContextService creates a new ContextService instance. reserveTokens are kept
+// free for the completion; summaryModel is used by the summarize strategy.
+func NewContextService(catalogService *CatalogService, akashService *AkashService, strategy, summaryModel string, reserveTokens int) *ContextService {
+	return &ContextService{
+		catalogService: catalogService,
+		akashService:   akashService,
+		strategy:       strategy,
+		summaryModel:   summaryModel,
+		reserveTokens:  reserveTokens,
+	}
+}
+
+// ValidateContextStrategy checks that strategy is a known context window strategy
+func ValidateContextStrategy(strategy string) error {
+	switch strategy {
+	case ContextReject, ContextTruncate, ContextSummarize:
+		return nil
+	default:
+		return fmt.Errorf("unknown context strategy: %s", strategy)
+	}
+}
+
+// Fit applies the configured strategy when req's prompt exceeds the token limit
+// of req.Model, rewriting req.Messages in place. Models without a known limit are
+// left untouched.
+func (s *ContextService) Fit(ctx context.Context, req *model.ChatCompletionRequest, sessionToken string) (ContextResult, error) {
+	m, ok, err := s.catalogService.Lookup(ctx, req.Model)
+	if err != nil || !ok {
+		m = model.Model{ID: req.Model}
+	}
+
+	tok := tokenizer.ForModel(m)
+	tokens := CountPromptTokens(tok, systemPrompt(*req), req.Messages)
+	result := ContextResult{Action: ContextActionNone, Tokens: tokens, Limit: m.TokenLimit}
+	if m.TokenLimit <= 0 {
+		return result, nil
+	}
+
+	budget := max(m.TokenLimit-s.reserveTokens, m.TokenLimit/2)
+	if tokens <= budget {
+		return result, nil
+	}
+
+	if s.strategy == ContextReject {
+		return result, &ContextLengthError{Model: req.Model, Limit: m.TokenLimit, Tokens: tokens}
+	}
+
+	kept, dropped := s.truncate(tok, systemPrompt(*req), req.Messages, budget)
+	if len(dropped) == 0 {
+		return result, &ContextLengthError{Model: req.Model, Limit: m.TokenLimit, Tokens: tokens}
+	}
+
+	result.Action = ContextActionTruncated
+	result.Dropped = len(dropped)
+
+	if s.strategy == ContextSummarize {
+		if summarized, ok := s.summarize(ctx, tok, req, sessionToken, kept, dropped, budget); ok {
+			kept = summarized
+			result.Action = ContextActionSummarized
+		}
+	}
+
+	result.Tokens = CountPromptTokens(tok, systemPrompt(*req), kept)
+	if result.Tokens > budget {
+		return result, &ContextLengthError{Model: req.Model, Limit: m.TokenLimit, Tokens: result.Tokens}
+	}
+
+	if req.OriginalMessages == nil {
+		req.OriginalMessages = req.Messages
+	}
+	req.Messages = kept
+	return result, nil
+}
+
+// truncate drops the oldest non-system messages until the prompt fits budget.
+// The last message is always kept, and the conversation never starts with an
+// assistant turn. It returns the kept messages and the dropped ones in order.
+func (s *ContextService) truncate(tok tokenizer.Tokenizer, system string, messages []model.ChatMessage, budget int) ([]model.ChatMessage, []model.ChatMessage) {
+	kept := append([]model.ChatMessage(nil), messages...)
+
+	// Count each message once so dropping turns stays linear in conversation length
+	counts := make([]int, len(kept))
+	total := tok.Count(system) + messageOverheadTokens
+	for i, msg := range kept {
+		counts[i] = tok.Count(msg.Content) + messageOverheadTokens
+		total += counts[i]
+	}
+
+	var dropped []model.ChatMessage
+	for total > budget || startsWithAssistant(kept) {
+		i := firstDroppable(kept)
+		if i < 0 {
+			break
+		}
+		dropped = append(dropped, kept[i])
+		total -= counts[i]
+		kept = append(kept[:i], kept[i+1:]...)
+		counts = append(counts[:i], counts[i+1:]...)
+	}
+
+	return kept, dropped
+}
+
+// summarize replaces the dropped messages with a summary produced by the summary
+// model, reporting false when summarization fails or does not fit
+func (s *ContextService) summarize(ctx context.Context, tok tokenizer.Tokenizer, req *model.ChatCompletionRequest, sessionToken string, kept, dropped []model.ChatMessage, budget int) ([]model.ChatMessage, bool) {
+	var transcript strings.Builder
+	for _, msg := range dropped {
+		transcript.WriteString(msg.Role + ": " + msg.Content + "\n\n")
+	}
+
+	summaryReq := model.ChatCompletionRequest{
+		Model: s.summaryModel,
+		Messages: []model.ChatMessage{{
+			Role:    "user",
+			Content: "Summarize the following conversation in a few short paragraphs. Keep names, facts, decisions and open questions. Reply with the summary only.\n\n" + transcript.String(),
+		}},
+		SystemPrompt: "You write concise, faithful summaries of conversations.",
+	}
+
+	completion, err := s.akashService.ProcessTextGeneration(ctx, summaryReq, sessionToken, 0.3, 0.95)
+	if err != nil || len(completion.Choices) == 0 || completion.Choices[0].Message.Content == "" {
+		logging.FromContext(ctx).Warn("context summarization failed, truncating instead", "summary_model", s.summaryModel, "error", err)
+		return nil, false
+	}
+
+	summary := model.ChatMessage{
+		Role:    "system",
+		Content: "Summary of the earlier conversation: " + completion.Choices[0].Message.Content,
+	}
+
+	// Place the summary after any leading system messages
+	i := 0
+	for i < len(kept) && kept[i].Role == "system" {
+		i++
+	}
+	summarized := append(append(append([]model.ChatMessage(nil), kept[:i]...), summary), kept[i:]...)
+
+	if CountPromptTokens(tok, systemPrompt(*req), summarized) > budget {
+		return nil, false
+	}
+	return summarized, true
+}
+
+// firstDroppable returns the index of the oldest non-system message other than the last, or -1
+func firstDroppable(messages []model.ChatMessage) int {
+	for i := 0; i < len(messages)-1; i++ {
+		if messages[i].Role != "system" {
+			return i
+		}
+	}
+	return -1
+}
+
+// startsWithAssistant reports whether the first non-system message is an assistant turn
+func startsWithAssistant(messages []model.ChatMessage) bool {
+	for _, msg := range messages {
+		if msg.Role != "system" {
+			return msg.Role == "assistant"
+		}
+	}
+	return false
+}
+
+// CountPromptTokens counts the prompt tokens sent for messages, including the system prompt
+func CountPromptTokens(tok tokenizer.Tokenizer, system string, messages []model.ChatMessage) int {
+	tokens := tok.Count(system) + messageOverheadTokens
+	for _, msg := range messages {
+		tokens += tok.Count(msg.Content) + messageOverheadTokens
+	}
+	return tokens
+}
+
+// CountRequestTokens counts the prompt tokens Akash receives for req, including the system prompt
+func CountRequestTokens(tok tokenizer.Tokenizer, req model.ChatCompletionRequest) int {
+	return CountPromptTokens(tok, systemPrompt(req), req.Messages)
+}
+
+// countUsage counts the prompt and completion tokens of a text generation
+func countUsage(tok tokenizer.Tokenizer, req model.ChatCompletionRequest, completion string) model.Usage {
+	usage := model.Usage{
+		PromptTokens:     CountRequestTokens(tok, req),
+		CompletionTokens: tok.Count(completion),
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+)
+
+// Health check statuses
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthFail     = "fail"
+)
+
+// maxCatalogAge is how old the last successful model catalog fetch may be before readiness degrades
+const maxCatalogAge = 30 * time.Minute
+
+// CatalogStatus reports when the model catalog was last fetched successfully
+type CatalogStatus interface {
+	LastSuccessfulFetch() time.Time
+}
+
+// HealthService performs readiness checks against Akash and caches the results
+type HealthService struct {
+	client         *akash.Client
+	sessionService *SessionService
+	catalog        CatalogStatus
+	breaker        *CircuitBreaker
+	ttl            time.Duration
+	probes         *upstreamProbes
+	probedAt       time.Time
+	probing        chan struct{}
+	mutex          sync.Mutex
+}
+
+// upstreamProbes are the results of the readiness checks that contact Akash
+type upstreamProbes struct {
+	upstream model.HealthCheck
+	session  model.HealthCheck
+}
+
+// NewHealthService creates a new HealthService instance
+func NewHealthService(client *akash.Client, sessionService *SessionService, catalog CatalogStatus, breaker *CircuitBreaker, ttl time.Duration) *HealthService {
+	return &HealthService{
+		client:         client,
+		sessionService: sessionService,
+		catalog:        catalog,
+		breaker:        breaker,
+		ttl:            ttl,
+	}
+}
+
+// Readiness returns the readiness report. The checks that contact Akash run at
+// most once per TTL; the catalog and circuit breaker checks are always current.
+func (h *HealthService) Readiness(ctx context.Context) model.ReadinessReport {
+	probes, cached := h.probe(ctx)
+	checks := map[string]model.HealthCheck{
+		"upstream":        probes.upstream,
+		"session":         probes.session,
+		"catalog":         h.checkCatalog(),
+		"circuit_breaker": h.checkBreaker(),
+	}
+
+	status := HealthOK
+	for _, check := range checks {
+		if check.Status == HealthFail {
+			status = HealthFail
+			break
+		}
+		if check.Status == HealthDegraded {
+			status = HealthDegraded
+		}
+	}
+
+	return model.ReadinessReport{
+		Status:    status,
+		Checks:    checks,
+		CheckedAt: time.Now().Unix(),
+		Cached:    cached,
+	}
+}
+
+// probe returns the upstream and session checks, reporting whether they were
+// cached. Concurrent callers share a single run of the probes, which happens
+// without holding the mutex.
+func (h *HealthService) probe(ctx context.Context) (upstreamProbes, bool) {
+	h.mutex.Lock()
+	if h.probes != nil && time.Since(h.probedAt) < h.ttl {
+		probes := *h.probes
+		h.mutex.Unlock()
+		return probes, true
+	}
+
+	// Wait for the probes another caller is already running
+	if done := h.probing; done != nil {
+		h.mutex.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			check := newHealthCheck(HealthFail, "readiness check cancelled", time.Now())
+			return upstreamProbes{upstream: check, session: check}, false
+		}
+
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		return *h.probes, true
+	}
+
+	done := make(chan struct{})
+	h.probing = done
+	h.mutex.Unlock()
+
+	// The probes are shared, so they must not be cut short when this caller goes away
+	ctx = context.WithoutCancel(ctx)
+
+	var probes upstreamProbes
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		probes.upstream = h.checkUpstream(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		probes.session = h.checkSession(ctx)
+	}()
+	wg.Wait()
+
+	h.mutex.Lock()
+	h.probes = &probes
+	h.probedAt = time.Now()
+	h.probing = nil
+	h.mutex.Unlock()
+	close(done)
+
+	return probes, false
+}
+
+// checkUpstream verifies that Akash chat is reachable
+func (h *HealthService) checkUpstream(ctx context.Context) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := h.client.Ping(ctx); err != nil {
+		return newHealthCheck(HealthFail, err.Error(), start)
+	}
+	return newHealthCheck(HealthOK, "", start)
+}
+
+// checkSession verifies that a session token can be acquired
+func (h *HealthService) checkSession(ctx context.Context) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := h.sessionService.GetSessionToken(ctx); err != nil {
+		return newHealthCheck(HealthFail, err.Error(), start)
+	}
+	return newHealthCheck(HealthOK, "", start)
+}
+
+// checkCatalog reports how recently the model catalog was fetched
+func (h *HealthService) checkCatalog() model.HealthCheck {
+	start := time.Now()
+	if h.catalog == nil {
+		return newHealthCheck(HealthDegraded, "model catalog not configured", start)
+	}
+
+	last := h.catalog.LastSuccessfulFetch()
+	if last.IsZero() {
+		return newHealthCheck(HealthDegraded, "model catalog has not been fetched yet", start)
+	}
+
+	age := time.Since(last).Round(time.Second)
+	if age > maxCatalogAge {
+		return newHealthCheck(HealthDegraded, fmt.Sprintf("last successful fetch %s ago", age), start)
+	}
+	return newHealthCheck(HealthOK, fmt.Sprintf("last successful fetch %s ago", age), start)
+}
+
+// checkBreaker reports the state of the circuit breaker guarding generation requests
+func (h *HealthService) checkBreaker() model.HealthCheck {
+	start := time.Now()
+	switch h.breaker.State() {
+	case BreakerOpen:
+		return newHealthCheck(HealthFail, "circuit breaker is open after repeated upstream failures", start)
+	case BreakerHalfOpen:
+		return newHealthCheck(HealthDegraded, "circuit breaker is half open, waiting for a trial request", start)
+	default:
+		return newHealthCheck(HealthOK, BreakerClosed, start)
+	}
+}
+
+// newHealthCheck builds a check result timed from start
+func newHealthCheck(status, message string, start time.Time) model.HealthCheck {
+	return model.HealthCheck{
+		Status:    status,
+		Message:   message,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: time.Now().Unix(),
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+)
+
+func TestReadinessSharesProbes(t *testing.T) {
+	var pings atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+		time.Sleep(100 * time.Millisecond)
+	})
+	mux.HandleFunc("/api/auth/session/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session_token=test-session; Path=/")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := akash.New(akash.WithBaseURL(server.URL))
+	breaker := NewCircuitBreaker(1, time.Minute)
+	h := NewHealthService(client, NewSessionService(client), nil, breaker, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report := h.Readiness(context.Background())
+			if report.Checks["upstream"].Status != HealthOK || report.Checks["session"].Status != HealthOK {
+				t.Errorf("checks = %+v", report.Checks)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := pings.Load(); n != 1 {
+		t.Errorf("upstream probed %d times by concurrent readiness checks, want 1", n)
+	}
+
+	// The breaker state is reported without waiting for the probe cache to expire
+	breaker.Record(&UpstreamStatusError{StatusCode: 502})
+	report := h.Readiness(context.Background())
+	if !report.Cached || report.Status != HealthFail || report.Checks["circuit_breaker"].Status != HealthFail {
+		t.Errorf("report = %+v, want a cached failure caused by the open circuit breaker", report)
+	}
+}
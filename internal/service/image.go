@@ -0,0 +1,332 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Image serving errors
+var (
+	ErrInvalidImageName       = errors.New("invalid image name")
+	ErrUnsupportedImageFormat = errors.New("unsupported image format")
+	ErrInvalidImageWidth      = errors.New("invalid image width")
+	ErrImageNotFound          = errors.New("image not found")
+	ErrImageTooLarge          = errors.New("image too large")
+)
+
+// imageWidths are the thumbnail widths that may be requested. The image route is
+// public, so only this fixed set of variants is rendered and cached.
+var imageWidths = []int{128, 256, 512, 1024}
+
+// Limits for images fetched from Akash, checked before they are decoded
+const (
+	maxImageBytes     = 32 << 20
+	maxImageDimension = 8192
+)
+
+var imageNameRegex = regexp.MustCompile(`^[A-Za-z0-9_\-]+\.(webp|png|jpe?g)$`)
+
+// ImageFile represents an image ready to be served
+type ImageFile struct {
+	Data        []byte
+	ContentType string
+}
+
+// ImageService fetches generated images from Akash and converts them on demand.
+// Generated images are handed to clients as URLs of the /v1/images proxy.
+type ImageService struct {
+	client        *akash.Client
+	publicURL     string
+	proxies       []netip.Prefix
+	cacheSize     int
+	cacheMaxBytes int
+	cacheBytes    int
+	cache         map[string]*ImageFile
+	order         []string
+	mutex         sync.Mutex
+}
+
+// NewImageService creates a new ImageService instance. The cache holds at most
+// cacheSize images totalling cacheMaxBytes. publicURL is the external base URL of
+// the proxy; when empty, proxy URLs are built from the incoming request.
+// trustedProxies is a comma-separated list of IPs and CIDRs whose
+// X-Forwarded-Proto and X-Forwarded-Host headers are honoured.
+func NewImageService(client *akash.Client, cacheSize, cacheMaxBytes int, publicURL, trustedProxies string) *ImageService {
+	s := &ImageService{
+		client:        client,
+		publicURL:     strings.TrimSuffix(publicURL, "/"),
+		cacheSize:     cacheSize,
+		cacheMaxBytes: cacheMaxBytes,
+		cache:         make(map[string]*ImageFile),
+	}
+	for _, entry := range strings.Split(trustedProxies, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			s.proxies = append(s.proxies, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			s.proxies = append(s.proxies, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return s
+}
+
+// BaseURL returns the external URL of the proxy for r. The public URL wins when
+// configured. Otherwise the request host is used, and forwarded headers are only
+// honoured when the request comes from a trusted proxy.
+func (s *ImageService) BaseURL(r *http.Request) string {
+	if s.publicURL != "" {
+		return s.publicURL
+	}
+
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if s.fromTrustedProxy(r.RemoteAddr) {
+		if proto := strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Proto"), ",")[0]); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwarded := strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Host"), ",")[0]); forwarded != "" {
+			host = forwarded
+		}
+	}
+	if host == "" {
+		return ""
+	}
+	return scheme + "://" + host
+}
+
+// fromTrustedProxy reports whether remoteAddr belongs to a trusted proxy
+func (s *ImageService) fromTrustedProxy(remoteAddr string) bool {
+	if len(s.proxies) == 0 {
+		return false
+	}
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, prefix := range s.proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyURL returns the /v1/images URL under which the image at upstreamURL is
+// served. base is the URL of the current request and is used when no public URL
+// is configured. upstreamURL is returned unchanged when neither is known.
+func (s *ImageService) ProxyURL(base, upstreamURL string) string {
+	if s.publicURL != "" {
+		base = s.publicURL
+	}
+	if base == "" {
+		return upstreamURL
+	}
+
+	u, err := url.Parse(upstreamURL)
+	if err != nil {
+		return upstreamURL
+	}
+	name := path.Base(u.Path)
+	if !imageNameRegex.MatchString(name) {
+		return upstreamURL
+	}
+	return strings.TrimSuffix(base, "/") + "/v1/images/" + name
+}
+
+// ProxyImages replaces the upstream URLs of images with proxy URLs, see ProxyURL
+func (s *ImageService) ProxyImages(base string, images []*model.ImageGenerationData) {
+	for _, image := range images {
+		image.Pic = s.ProxyURL(base, image.Pic)
+	}
+}
+
+// GetImage returns the named image, converted to format and scaled down to width if requested
+func (s *ImageService) GetImage(ctx context.Context, name, format string, width int) (*ImageFile, error) {
+	if !imageNameRegex.MatchString(name) {
+		return nil, ErrInvalidImageName
+	}
+
+	format = normalizeImageFormat(format)
+	if format == "" {
+		format = normalizeImageFormat(strings.TrimPrefix(path.Ext(name), "."))
+	}
+	if format != "png" && format != "jpeg" && format != "webp" {
+		return nil, ErrUnsupportedImageFormat
+	}
+
+	if width != 0 && !slices.Contains(imageWidths, width) {
+		return nil, fmt.Errorf("%w: must be one of %v", ErrInvalidImageWidth, imageWidths)
+	}
+
+	key := fmt.Sprintf("%s|%s|%d", name, format, width)
+	if file := s.getCached(key); file != nil {
+		return file, nil
+	}
+
+	original, err := s.getOriginal(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serve the upstream bytes untouched when no conversion is needed
+	if width == 0 && contentTypeForFormat(format) == original.ContentType {
+		return original, nil
+	}
+
+	// Check the dimensions before decoding allocates the full image
+	info, _, err := image.DecodeConfig(bytes.NewReader(original.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if info.Width > maxImageDimension || info.Height > maxImageDimension {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(original.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if width > 0 && width < img.Bounds().Dx() {
+		img = resizeImage(img, width)
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	case "webp":
+		err = nativewebp.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	file := &ImageFile{
+		Data:        buf.Bytes(),
+		ContentType: contentTypeForFormat(format),
+	}
+	s.putCached(key, file)
+
+	return file, nil
+}
+
+// getOriginal fetches the unmodified image from Akash, using the cache when possible
+func (s *ImageService) getOriginal(ctx context.Context, name string) (*ImageFile, error) {
+	key := name + "|original"
+	if file := s.getCached(key); file != nil {
+		return file, nil
+	}
+
+	body, err := s.client.FetchImage(ctx, name)
+	if errors.Is(err, akash.ErrImageNotFound) {
+		return nil, ErrImageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxImageBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > maxImageBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	file := &ImageFile{
+		Data:        data,
+		ContentType: http.DetectContentType(data),
+	}
+	s.putCached(key, file)
+
+	return file, nil
+}
+
+// getCached returns a cached image or nil
+func (s *ImageService) getCached(key string) *ImageFile {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.cache[key]
+}
+
+// putCached stores an image, evicting the oldest entries until it fits in the cache
+func (s *ImageService) putCached(key string, file *ImageFile) {
+	if s.cacheSize <= 0 || len(file.Data) > s.cacheMaxBytes {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.cache[key]; ok {
+		return
+	}
+
+	for len(s.order) >= s.cacheSize || s.cacheBytes+len(file.Data) > s.cacheMaxBytes {
+		s.cacheBytes -= len(s.cache[s.order[0]].Data)
+		delete(s.cache, s.order[0])
+		s.order = s.order[1:]
+	}
+
+	s.cache[key] = file
+	s.cacheBytes += len(file.Data)
+	s.order = append(s.order, key)
+}
+
+// resizeImage scales img down to width, preserving the aspect ratio
+func resizeImage(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// normalizeImageFormat maps format aliases to their canonical name
+func normalizeImageFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "jpg" {
+		return "jpeg"
+	}
+	return format
+}
+
+// contentTypeForFormat returns the MIME type for a canonical image format
+func contentTypeForFormat(format string) string {
+	return "image/" + format
+}
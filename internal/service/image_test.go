@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+)
+
+// encodePNG returns a blank PNG of the given size
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newImageUpstream serves images by name from Akash's /api/image path
+func newImageUpstream(t *testing.T, images map[string][]byte) *akash.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := images[r.URL.Path[len("/api/image/"):]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return akash.New(akash.WithBaseURL(server.URL))
+}
+
+func TestImageServiceProxyURL(t *testing.T) {
+	upstream := "https://chat.akash.network/api/image/abc-123.webp"
+
+	s := NewImageService(nil, 0, 0, "", "")
+	if got := s.ProxyURL("http://localhost:16571", upstream); got != "http://localhost:16571/v1/images/abc-123.webp" {
+		t.Errorf("ProxyURL() = %q", got)
+	}
+	if got := s.ProxyURL("", upstream); got != upstream {
+		t.Errorf("ProxyURL() without a base = %q, want the upstream URL", got)
+	}
+	if got := s.ProxyURL("http://localhost:16571", "https://example.com/no-extension"); got != "https://example.com/no-extension" {
+		t.Errorf("ProxyURL() of an unexpected URL = %q, want it unchanged", got)
+	}
+
+	s = NewImageService(nil, 0, 0, "https://images.example.com/", "")
+	images := []*model.ImageGenerationData{{Pic: upstream}}
+	s.ProxyImages("http://localhost:16571", images)
+	if images[0].Pic != "https://images.example.com/v1/images/abc-123.webp" {
+		t.Errorf("ProxyImages() with a public URL = %q", images[0].Pic)
+	}
+}
+
+func TestImageServiceBaseURL(t *testing.T) {
+	request := func(remoteAddr string, headers map[string]string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://proxy.internal/v1/images/a.png", nil)
+		r.RemoteAddr = remoteAddr
+		for name, value := range headers {
+			r.Header.Set(name, value)
+		}
+		return r
+	}
+	forwarded := map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "images.example.com"}
+
+	tests := []struct {
+		name      string
+		publicURL string
+		proxies   string
+		request   *http.Request
+		want      string
+	}{
+		{"request host", "", "", request("203.0.113.7:4000", nil), "http://proxy.internal"},
+		{"untrusted forwarded headers", "", "", request("203.0.113.7:4000", forwarded), "http://proxy.internal"},
+		{"peer outside trusted proxies", "", "10.0.0.0/8", request("203.0.113.7:4000", forwarded), "http://proxy.internal"},
+		{"trusted proxy CIDR", "", "10.0.0.0/8", request("10.1.2.3:4000", forwarded), "https://images.example.com"},
+		{"trusted proxy IP", "", "192.0.2.1, ::1", request("[::1]:4000", forwarded), "https://images.example.com"},
+		{"invalid forwarded proto", "", "10.0.0.0/8", request("10.1.2.3:4000", map[string]string{"X-Forwarded-Proto": "javascript"}), "http://proxy.internal"},
+		{"public URL wins", "https://public.example.com/", "10.0.0.0/8", request("10.1.2.3:4000", forwarded), "https://public.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewImageService(nil, 0, 0, tt.publicURL, tt.proxies)
+			if got := s.BaseURL(tt.request); got != tt.want {
+				t.Errorf("BaseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageServiceWidths(t *testing.T) {
+	client := newImageUpstream(t, map[string][]byte{"a.png": encodePNG(t, 600, 300)})
+	s := NewImageService(client, 8, 1<<20, "", "")
+
+	for _, width := range []int{-1, 1, 300, 2048} {
+		if _, err := s.GetImage(context.Background(), "a.png", "", width); !errors.Is(err, ErrInvalidImageWidth) {
+			t.Errorf("GetImage(width=%d) error = %v, want ErrInvalidImageWidth", width, err)
+		}
+	}
+	if len(s.cache) != 0 {
+		t.Errorf("rejected widths left %d cache entries", len(s.cache))
+	}
+
+	file, err := s.GetImage(context.Background(), "a.png", "", 256)
+	if err != nil {
+		t.Fatalf("GetImage(width=256) error = %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(file.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != 256 || size.Y != 128 {
+		t.Errorf("thumbnail size = %v, want 256x128", size)
+	}
+}
+
+func TestImageServiceRejectsOversizedImages(t *testing.T) {
+	client := newImageUpstream(t, map[string][]byte{
+		"huge.png": make([]byte, maxImageBytes+1),
+		"wide.png": encodePNG(t, maxImageDimension+1, 1),
+	})
+	s := NewImageService(client, 8, 1<<20, "", "")
+
+	// The byte limit applies when the image is fetched
+	if _, err := s.GetImage(context.Background(), "huge.png", "", 0); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("GetImage(huge.png) error = %v, want ErrImageTooLarge", err)
+	}
+	// The dimension limit applies before an image is decoded for conversion
+	if _, err := s.GetImage(context.Background(), "wide.png", "jpeg", 0); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("GetImage(wide.png) error = %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestImageServiceCacheByteBudget(t *testing.T) {
+	s := NewImageService(nil, 10, 100, "", "")
+
+	s.putCached("a", &ImageFile{Data: make([]byte, 60)})
+	s.putCached("b", &ImageFile{Data: make([]byte, 30)})
+	s.putCached("c", &ImageFile{Data: make([]byte, 40)})
+	s.putCached("big", &ImageFile{Data: make([]byte, 101)})
+
+	if s.getCached("a") != nil {
+		t.Error("oldest entry was not evicted to stay within the byte budget")
+	}
+	if s.getCached("b") == nil || s.getCached("c") == nil {
+		t.Error("entries within the byte budget were evicted")
+	}
+	if s.getCached("big") != nil {
+		t.Error("entry larger than the byte budget was cached")
+	}
+	if s.cacheBytes != 70 {
+		t.Errorf("cacheBytes = %d, want 70", s.cacheBytes)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/tokenizer"
+)
+
+// Finish reasons reported when generation ends
+const (
+	FinishStop   = "stop"
+	FinishLength = "length"
+)
+
+// outputLimiter enforces max_tokens and stop sequences on generated text, which
+// Akash does not support upstream. Text that could be the start of a stop
+// sequence is held back until it can be ruled out.
+type outputLimiter struct {
+	tok       tokenizer.Tokenizer
+	maxTokens int
+	stop      []string
+	tokens    int
+	pending   string
+}
+
+// newOutputLimiter creates a limiter for req counting tokens with tok, or returns
+// nil when req sets no limits
+func newOutputLimiter(tok tokenizer.Tokenizer, req model.ChatCompletionRequest) *outputLimiter {
+	var stop []string
+	for _, s := range req.Stop {
+		if s != "" {
+			stop = append(stop, s)
+		}
+	}
+
+	maxTokens := 0
+	if req.MaxTokens != nil && *req.MaxTokens > 0 {
+		maxTokens = *req.MaxTokens
+	}
+
+	if maxTokens == 0 && len(stop) == 0 {
+		return nil
+	}
+	return &outputLimiter{
+		tok:       tok,
+		maxTokens: maxTokens,
+		stop:      stop,
+	}
+}
+
+// Push adds generated content and returns the text that may be emitted. When a
+// limit is reached done is true, reason is the finish reason and stopSequence is
+// the stop sequence that matched, if any.
+func (l *outputLimiter) Push(content string) (text string, done bool, reason, stopSequence string) {
+	buf := l.pending + content
+	l.pending = ""
+
+	// Cut at the earliest stop sequence
+	cut, matched := -1, ""
+	for _, s := range l.stop {
+		if i := strings.Index(buf, s); i >= 0 && (cut < 0 || i < cut) {
+			cut, matched = i, s
+		}
+	}
+	if cut >= 0 {
+		text, done, reason = l.take(buf[:cut])
+		if done {
+			return text, true, reason, ""
+		}
+		return text, true, FinishStop, matched
+	}
+
+	// Hold back a suffix that may begin a stop sequence
+	hold := 0
+	for _, s := range l.stop {
+		for n := min(len(s)-1, len(buf)); n > hold; n-- {
+			if strings.HasSuffix(buf, s[:n]) {
+				hold = n
+				break
+			}
+		}
+	}
+	l.pending = buf[len(buf)-hold:]
+
+	text, done, reason = l.take(buf[:len(buf)-hold])
+	return text, done, reason, ""
+}
+
+// Flush returns any held back text once generation has ended, with the same
+// results as Push
+func (l *outputLimiter) Flush() (text string, done bool, reason, stopSequence string) {
+	buf := l.pending
+	l.pending = ""
+	text, done, reason = l.take(buf)
+	return text, done, reason, ""
+}
+
+// take returns as much of text as fits within the token limit
+func (l *outputLimiter) take(text string) (string, bool, string) {
+	if l.maxTokens == 0 || text == "" {
+		return text, false, ""
+	}
+
+	count := l.tok.Count(text)
+	if l.tokens+count <= l.maxTokens {
+		l.tokens += count
+		return text, false, ""
+	}
+
+	// Find the longest prefix that fits, on rune boundaries
+	runes := []rune(text)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if l.tokens+l.tok.Count(string(runes[:mid])) <= l.maxTokens {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	l.tokens = l.maxTokens
+	return string(runes[:lo]), true, FinishLength
+}
+
+// applyLimits truncates a complete generation to req's limits, returning the text,
+// the finish reason to report instead of finishReason and any matched stop sequence
+func applyLimits(tok tokenizer.Tokenizer, req model.ChatCompletionRequest, content, finishReason string) (string, string, string) {
+	limiter := newOutputLimiter(tok, req)
+	if limiter == nil {
+		return content, finishReason, ""
+	}
+
+	text, done, reason, stopSequence := limiter.Push(content)
+	if done {
+		return text, reason, stopSequence
+	}
+
+	rest, done, reason, _ := limiter.Flush()
+	if done {
+		return text + rest, reason, ""
+	}
+	return text + rest, finishReason, ""
+}
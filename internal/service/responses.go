@@ -0,0 +1,85 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+// StoredResponse is a response kept for retrieval and previous_response_id chaining
+type StoredResponse struct {
+	Response *model.Response
+	// Messages is the conversation up to and including the response output,
+	// without the request instructions
+	Messages []model.ChatMessage
+	// Owner is the name of the API key that created the response, empty when
+	// authentication is disabled
+	Owner string
+}
+
+// ResponseStore keeps the most recent Responses API results in memory
+type ResponseStore struct {
+	responses map[string]*StoredResponse
+	order     []string
+	size      int
+	mutex     sync.RWMutex
+}
+
+// NewResponseStore creates a new ResponseStore holding at most size responses
+func NewResponseStore(size int) *ResponseStore {
+	if size < 1 {
+		size = 1
+	}
+
+	return &ResponseStore{
+		responses: make(map[string]*StoredResponse),
+		size:      size,
+	}
+}
+
+// Put stores a response, evicting the oldest when full
+func (s *ResponseStore) Put(stored *StoredResponse) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id := stored.Response.ID
+	if _, ok := s.responses[id]; !ok {
+		for len(s.order) >= s.size {
+			delete(s.responses, s.order[0])
+			s.order = s.order[1:]
+		}
+		s.order = append(s.order, id)
+	}
+	s.responses[id] = stored
+}
+
+// Get returns a stored response created by owner. Responses of other owners are
+// reported as missing.
+func (s *ResponseStore) Get(id, owner string) (*StoredResponse, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stored, ok := s.responses[id]
+	if !ok || stored.Owner != owner {
+		return nil, false
+	}
+	return stored, true
+}
+
+// Delete removes a stored response created by owner, reporting whether it existed
+func (s *ResponseStore) Delete(id, owner string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if stored, ok := s.responses[id]; !ok || stored.Owner != owner {
+		return false
+	}
+	delete(s.responses, id)
+	for i, stored := range s.order {
+		if stored == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SessionError is returned when no Akash session token could be obtained
+type SessionError struct {
+	Err error
+}
+
+func (e *SessionError) Error() string {
+	return "failed to get session token: " + e.Err.Error()
+}
+
+func (e *SessionError) Unwrap() error {
+	return e.Err
+}
+
+// RouterService selects the model serving a request and prepares the request for
+// generation. The HTTP, gRPC and MCP front ends all route requests through it.
+type RouterService struct {
+	sessionService *SessionService
+	catalogService *CatalogService
+	aliasService   *AliasService
+	contextService *ContextService
+}
+
+// NewRouterService creates a new RouterService instance
+func NewRouterService(sessionService *SessionService, catalogService *CatalogService, aliasService *AliasService, contextService *ContextService) *RouterService {
+	return &RouterService{
+		sessionService: sessionService,
+		catalogService: catalogService,
+		aliasService:   aliasService,
+		contextService: contextService,
+	}
+}
+
+// Route resolves aliases and virtual models in req, then selects the first model of
+// its fallback chain that the catalog reports as available. A *ModelNotFoundError
+// is returned when no model of the chain may be used.
+func (r *RouterService) Route(ctx context.Context, req *model.ChatCompletionRequest) error {
+	span := trace.SpanFromContext(ctx)
+
+	requested := req.Model
+	if r.aliasService.Resolve(req) {
+		logging.Set(ctx, logging.FieldModelAlias, requested)
+		span.SetAttributes(attribute.String("akash.model_alias", requested))
+	}
+
+	// Skip models in the fallback chain that the catalog reports as unknown or unavailable
+	candidates := r.catalogService.Available(ctx, append([]string{req.Model}, r.aliasService.Fallbacks(req.Model)...))
+	if len(candidates) == 0 {
+		if err := r.catalogService.Validate(ctx, req.Model); err != nil {
+			return err
+		}
+		return &ModelNotFoundError{Model: req.Model}
+	}
+	req.Model, req.Fallbacks = candidates[0], candidates[1:]
+
+	span.SetAttributes(attribute.String("akash.model", req.Model))
+	return nil
+}
+
+// Prepare gets a session token and fits text prompts into the context window of
+// req.Model. Session failures are returned as *SessionError and prompts that cannot
+// be fitted as *ContextLengthError.
+func (r *RouterService) Prepare(ctx context.Context, req *model.ChatCompletionRequest) (string, ContextResult, error) {
+	sessionToken, err := r.sessionService.GetSessionToken(ctx)
+	if err != nil {
+		tracing.RecordError(trace.SpanFromContext(ctx), err)
+		return "", ContextResult{}, &SessionError{Err: err}
+	}
+
+	if req.Model == akash.DefaultImageModel {
+		return sessionToken, ContextResult{}, nil
+	}
+
+	result, err := r.contextService.Fit(ctx, req, sessionToken)
+	logging.Set(ctx, logging.FieldContextAction, result.Action)
+	if err != nil {
+		return "", result, err
+	}
+	return sessionToken, result, nil
+}
+
+// WithFallback calls fn with req.Model and then with each of req.Fallbacks for as
+// long as the failure is retryable, leaving req.Model set to the last model tried.
+// The prompt is fitted again into the context window of every fallback model, and
+// fallbacks it cannot be fitted into are skipped. A non-nil committed func stops
+// retrying once a response has been written.
+func (r *RouterService) WithFallback(ctx context.Context, req *model.ChatCompletionRequest, committed func() bool, fn func(model.ChatCompletionRequest) error) error {
+	models := append([]string{req.Model}, req.Fallbacks...)
+
+	var err error
+	for i, m := range models {
+		req.Model = m
+		if i == 0 {
+			err = fn(*req)
+		} else if err = r.refit(ctx, req); err == nil {
+			err = fn(*req)
+		}
+		if err == nil {
+			return nil
+		}
+
+		var lengthErr *ContextLengthError
+		skippable := errors.As(err, &lengthErr) || IsRetryable(err)
+		if i == len(models)-1 || !skippable || ctx.Err() != nil || (committed != nil && committed()) {
+			return err
+		}
+		logging.FromContext(ctx).Warn("model request failed, trying fallback", "model", m, "fallback", models[i+1], "error", err)
+	}
+	return err
+}
+
+// refit fits the original prompt of req into the context window of req.Model
+func (r *RouterService) refit(ctx context.Context, req *model.ChatCompletionRequest) error {
+	if req.Model == akash.DefaultImageModel {
+		return nil
+	}
+	if req.OriginalMessages != nil {
+		req.Messages = req.OriginalMessages
+	}
+
+	sessionToken, err := r.sessionService.GetSessionToken(ctx)
+	if err != nil {
+		return &SessionError{Err: err}
+	}
+	result, err := r.contextService.Fit(ctx, req, sessionToken)
+	logging.Set(ctx, logging.FieldContextAction, result.Action)
+	return err
+}
+
+// Image builds an image generation request for prompt and routes it like a chat
+// request, so aliases of the image model resolve too. modelID defaults to AkashGen.
+func (r *RouterService) Image(ctx context.Context, modelID, prompt string, opts *model.ImageOptions) (model.ChatCompletionRequest, error) {
+	if modelID == "" {
+		modelID = akash.DefaultImageModel
+	}
+	req := model.ChatCompletionRequest{
+		Messages: []model.ChatMessage{{Role: "user", Content: prompt}},
+		Model:    modelID,
+		Image:    opts,
+	}
+	return req, r.Route(ctx, &req)
+}
+
+// SamplingParams returns the request's temperature and topP, falling back to the
+// Akash image defaults for AkashGen and to the text defaults otherwise
+func SamplingParams(req model.ChatCompletionRequest) (float64, float64) {
+	temperature, topP := akash.DefaultTemperature, akash.DefaultTopP
+	if req.Model == akash.DefaultImageModel {
+		temperature, topP = akash.DefaultImageTemperature, akash.DefaultImageTopP
+	}
+
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+	if req.TopP != nil {
+		topP = *req.TopP
+	}
+	return temperature, topP
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/logging"
+	"github.com/006lp/akashchat-api-go/internal/metrics"
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/internal/tokenizer"
+	"github.com/006lp/akashchat-api-go/internal/tracing"
+	"github.com/006lp/akashchat-api-go/pkg/akash"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Stream event types
+const (
+	StreamStart  = "start"
+	StreamDelta  = "delta"
+	StreamFinish = "finish"
+)
+
+// StreamEvent is a single event decoded from the Akash data stream. Start
+// carries the upstream message ID, delta carries generated text and finish
+// carries the finish reason, any matched stop sequence and token usage.
+type StreamEvent struct {
+	Type         string
+	MessageID    string
+	Content      string
+	FinishReason string
+	StopSequence string
+	Usage        model.Usage
+}
+
+// StreamHandler receives stream events; returning an error stops the stream
+type StreamHandler func(StreamEvent) error
+
+// errStreamDone stops reading the upstream stream once a limit has been reached
+var errStreamDone = errors.New("stream done")
+
+// StreamTextGeneration sends a text generation request and passes each decoded
+// stream event to handle. A finish event is always sent once the stream has
+// started and ends without error.
+func (a *AkashService) StreamTextGeneration(ctx context.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64, handle StreamHandler) error {
+	ctx, span := tracing.Start(ctx, "AkashService.processStream", attribute.String("akash.model", req.Model))
+	defer span.End()
+
+	if err := a.breaker.Allow(); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	// Only the upstream response counts towards the circuit breaker, not errors
+	// returned by handle while forwarding the stream to the client
+	var upstreamErr error
+	stream := a.client.ChatStream(ctx, chatRequest(req, sessionToken, temperature, topP))
+	err := a.processStream(ctx, func(yield func(akash.StreamEvent, error) bool) {
+		for event, err := range stream {
+			if err != nil {
+				upstreamErr = err
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}, req, time.Now(), handle)
+	a.breaker.Record(upstreamErr)
+
+	tracing.RecordError(span, err)
+	return err
+}
+
+// Generation is a complete text generation collected from the stream
+type Generation struct {
+	MessageID    string
+	Content      string
+	FinishReason string
+	StopSequence string
+	Usage        model.Usage
+}
+
+// GenerateText runs a text generation to completion, applying the request's output limits
+func (a *AkashService) GenerateText(ctx context.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64) (*Generation, error) {
+	var generation Generation
+	var content strings.Builder
+
+	err := a.StreamTextGeneration(ctx, req, sessionToken, temperature, topP, func(event StreamEvent) error {
+		switch event.Type {
+		case StreamStart:
+			generation.MessageID = event.MessageID
+		case StreamDelta:
+			content.WriteString(event.Content)
+		case StreamFinish:
+			generation.FinishReason = event.FinishReason
+			generation.StopSequence = event.StopSequence
+			generation.Usage = event.Usage
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	generation.Content = content.String()
+	return &generation, nil
+}
+
+// ProcessTextGenerationStream handles text generation requests with streaming,
+// writing OpenAI chat completion chunks to writer
+func (a *AkashService) ProcessTextGenerationStream(ctx context.Context, req model.ChatCompletionRequest, sessionToken string, temperature, topP float64, writer io.Writer) error {
+	var messageID string
+	chunk := func(delta model.Delta, finishReason string, usage *model.Usage) model.OpenAIStreamCompletion {
+		return model.OpenAIStreamCompletion{
+			ID:      messageID,
+			Object:  "chat.completion.chunk",
+			Created: time.Now().Unix(),
+			Model:   req.Model,
+			Choices: []model.OpenAIStreamChoice{
+				{
+					Index:        0,
+					Delta:        delta,
+					FinishReason: finishReason,
+				},
+			},
+			Usage: usage,
+		}
+	}
+
+	return a.StreamTextGeneration(ctx, req, sessionToken, temperature, topP, func(event StreamEvent) error {
+		switch event.Type {
+		case StreamStart:
+			messageID = "chatcmpl-" + event.MessageID
+			a.writeStreamResponse(writer, chunk(model.Delta{Role: "assistant"}, "", nil))
+		case StreamDelta:
+			a.writeStreamResponse(writer, chunk(model.Delta{Content: event.Content}, "", nil))
+		case StreamFinish:
+			a.writeStreamResponse(writer, chunk(model.Delta{}, event.FinishReason, &event.Usage))
+		}
+		return nil
+	})
+}
+
+// processStream forwards the Akash stream events, applying the request's
+// output limits and counting token usage
+func (a *AkashService) processStream(ctx context.Context, stream iter.Seq2[akash.StreamEvent, error], req model.ChatCompletionRequest, start time.Time, handle StreamHandler) error {
+	tok := a.Tokenizer(ctx, req.Model)
+	limiter := newOutputLimiter(tok, req)
+	var completion strings.Builder
+
+	// Track time-to-first-token and throughput
+	var firstToken time.Time
+	defer func() {
+		metrics.ObserveStream(req.Model, start, firstToken, countUsage(tok, req, completion.String()).CompletionTokens)
+	}()
+
+	// emit forwards generated text, finishing the stream once a limit is reached
+	emit := func(text string, done bool, reason, stopSequence string) error {
+		if text != "" {
+			completion.WriteString(text)
+			if err := handle(StreamEvent{Type: StreamDelta, Content: text}); err != nil {
+				return err
+			}
+		}
+		if !done {
+			return nil
+		}
+		if err := finish(tok, req, completion.String(), reason, stopSequence, handle); err != nil {
+			return err
+		}
+		return errStreamDone
+	}
+
+	// end flushes held back text and sends the finish event
+	end := func(finishReason string) error {
+		if limiter != nil {
+			err := emit(limiter.Flush())
+			if errors.Is(err, errStreamDone) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return finish(tok, req, completion.String(), finishReason, "", handle)
+	}
+
+	for event, err := range stream {
+		if err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case akash.EventStart:
+			if event.MessageID != "" {
+				logging.Set(ctx, logging.FieldUpstreamMessageID, event.MessageID)
+			}
+			if err := handle(StreamEvent{Type: StreamStart, MessageID: event.MessageID}); err != nil {
+				return err
+			}
+
+		case akash.EventDelta:
+			if firstToken.IsZero() {
+				firstToken = time.Now()
+			}
+
+			var err error
+			if limiter != nil {
+				err = emit(limiter.Push(event.Content))
+			} else {
+				err = emit(event.Content, false, "", "")
+			}
+			if errors.Is(err, errStreamDone) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+		case akash.EventFinish:
+			return end(event.FinishReason)
+		}
+	}
+
+	return nil
+}
+
+// finish sends the finish event with the token usage of the completed generation
+func finish(tok tokenizer.Tokenizer, req model.ChatCompletionRequest, completion, finishReason, stopSequence string, handle StreamHandler) error {
+	return handle(StreamEvent{
+		Type:         StreamFinish,
+		FinishReason: finishReason,
+		StopSequence: stopSequence,
+		Usage:        countUsage(tok, req, completion),
+	})
+}
+
+func (a *AkashService) writeStreamResponse(writer io.Writer, resp model.OpenAIStreamCompletion) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(resp)
+
+	fmt.Fprintf(writer, "data: %s\n\n", buf.String())
+	if flusher, ok := writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
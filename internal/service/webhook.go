@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/006lp/akashchat-api-go/pkg/client"
+)
+
+var (
+	// ErrInvalidCallbackURL is returned when a callback URL is not an absolute http(s) URL
+	ErrInvalidCallbackURL = errors.New("invalid callback url")
+	// ErrForbiddenCallbackURL is returned when a callback URL points at a private,
+	// loopback or link-local address that is not explicitly allowed
+	ErrForbiddenCallbackURL = errors.New("callback url resolves to a forbidden address")
+	// ErrWebhooksDisabled is returned when callbacks are requested but no signing secret is configured
+	ErrWebhooksDisabled = errors.New("callbacks are disabled because WEBHOOK_SECRET is not set")
+)
+
+// cgnatPrefix is the carrier-grade NAT range, which net.IP does not treat as private
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// maxWebhookDeliveries is the number of delivery records kept for inspection
+const maxWebhookDeliveries = 1000
+
+// WebhookService delivers signed job results to client callback URLs
+type WebhookService struct {
+	httpClient   *client.HTTPClient
+	resolver     *net.Resolver
+	secret       string
+	maxAttempts  int
+	allowedHosts map[string]bool
+	allowedNets  []netip.Prefix
+	deliveries   map[string]*model.WebhookDelivery
+	order        []string
+	mutex        sync.RWMutex
+}
+
+// NewWebhookService creates a new WebhookService instance. allowedHosts is a
+// comma separated list of host names and CIDR ranges that callbacks may reach
+// even though they resolve to private, loopback or link-local addresses.
+func NewWebhookService(secret string, maxAttempts int, allowedHosts string) *WebhookService {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	w := &WebhookService{
+		resolver:     net.DefaultResolver,
+		secret:       secret,
+		maxAttempts:  maxAttempts,
+		allowedHosts: make(map[string]bool),
+		deliveries:   make(map[string]*model.WebhookDelivery),
+	}
+	for _, entry := range strings.Split(allowedHosts, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			w.allowedNets = append(w.allowedNets, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			w.allowedNets = append(w.allowedNets, netip.PrefixFrom(addr, addr.BitLen()))
+		} else {
+			w.allowedHosts[entry] = true
+		}
+	}
+
+	// Addresses are checked again when connecting, so a host cannot pass
+	// validation and then rebind its DNS record to an internal address
+	w.httpClient = client.WrapHTTPClient(&http.Client{
+		Timeout: 60 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         w.dialContext,
+			ForceAttemptHTTP2:   true,
+			TLSHandshakeTimeout: 10 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	})
+	return w
+}
+
+// ValidateCallbackURL checks that callbacks are enabled and that callbackURL is an
+// absolute http(s) URL whose host only resolves to public addresses
+func (w *WebhookService) ValidateCallbackURL(ctx context.Context, callbackURL string) error {
+	if w.secret == "" {
+		return ErrWebhooksDisabled
+	}
+
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidCallbackURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if w.allowedHosts[host] {
+		return nil
+	}
+
+	addrs, err := w.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: failed to resolve %s: %v", ErrInvalidCallbackURL, host, err)
+	}
+	for _, addr := range addrs {
+		if !w.addrAllowed(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenCallbackURL, host, addr)
+		}
+	}
+	return nil
+}
+
+// dialContext connects to a callback host, refusing forbidden addresses unless the
+// host is allowlisted
+func (w *WebhookService) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if !w.allowedHosts[strings.ToLower(host)] {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			ip, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(ip)
+			if err != nil {
+				return err
+			}
+			if !w.addrAllowed(addr) {
+				return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenCallbackURL, host, addr)
+			}
+			return nil
+		}
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// addrAllowed reports whether callbacks may connect to addr
+func (w *WebhookService) addrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range w.allowedNets {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	// Link-local covers the 169.254.169.254 cloud metadata endpoint
+	return !(addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified() || cgnatPrefix.Contains(addr))
+}
+
+// Dispatch delivers payload to callbackURL in the background, retrying with exponential backoff
+func (w *WebhookService) Dispatch(callbackURL string, payload model.WebhookPayload) {
+	delivery := &model.WebhookDelivery{
+		ID:          payload.ID,
+		Event:       payload.Event,
+		CallbackURL: callbackURL,
+		Status:      "pending",
+		Attempts:    []model.WebhookAttempt{},
+	}
+	w.putDelivery(delivery)
+
+	go w.deliver(delivery, payload)
+}
+
+// GetDelivery returns a snapshot of the delivery record for a job
+func (w *WebhookService) GetDelivery(id string) (*model.WebhookDelivery, bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	delivery, ok := w.deliveries[id]
+	if !ok {
+		return nil, false
+	}
+
+	snapshot := *delivery
+	snapshot.Attempts = append([]model.WebhookAttempt(nil), delivery.Attempts...)
+	return &snapshot, true
+}
+
+// deliver performs the delivery attempts for a payload
+func (w *WebhookService) deliver(delivery *model.WebhookDelivery, payload model.WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		w.recordAttempt(delivery, model.WebhookAttempt{
+			Attempt:   1,
+			Error:     fmt.Sprintf("failed to marshal payload: %v", err),
+			Timestamp: time.Now().Unix(),
+		}, "failed")
+		return
+	}
+
+	backoff := 1 * time.Second
+	for attempt := 1; attempt <= w.maxAttempts; attempt++ {
+		record := model.WebhookAttempt{
+			Attempt:   attempt,
+			Timestamp: time.Now().Unix(),
+		}
+
+		// Every attempt is signed with its own timestamp so receivers can reject replays
+		timestamp := strconv.FormatInt(record.Timestamp, 10)
+		headers := map[string]string{
+			"Content-Type":        "application/json",
+			"X-Webhook-ID":        payload.ID,
+			"X-Webhook-Event":     payload.Event,
+			"X-Webhook-Timestamp": timestamp,
+			"X-Webhook-Signature": "sha256=" + w.sign(timestamp, body),
+		}
+
+		resp, err := w.httpClient.Post(delivery.CallbackURL, bytes.NewReader(body), headers)
+		if err != nil {
+			record.Error = err.Error()
+		} else {
+			resp.Body.Close()
+			record.StatusCode = resp.StatusCode
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				w.recordAttempt(delivery, record, "delivered")
+				return
+			}
+			record.Error = fmt.Sprintf("callback responded with status: %d", resp.StatusCode)
+		}
+
+		if attempt == w.maxAttempts {
+			w.recordAttempt(delivery, record, "failed")
+			slog.Warn("webhook delivery failed",
+				"job_id", delivery.ID,
+				"callback_url", delivery.CallbackURL,
+				"attempts", attempt,
+				"error", record.Error,
+			)
+			return
+		}
+		w.recordAttempt(delivery, record, "retrying")
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of "<timestamp>.<body>" using the configured secret
+func (w *WebhookService) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// putDelivery stores a delivery record, evicting the oldest when full
+func (w *WebhookService) putDelivery(delivery *model.WebhookDelivery) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, ok := w.deliveries[delivery.ID]; !ok {
+		for len(w.order) >= maxWebhookDeliveries {
+			delete(w.deliveries, w.order[0])
+			w.order = w.order[1:]
+		}
+		w.order = append(w.order, delivery.ID)
+	}
+	w.deliveries[delivery.ID] = delivery
+}
+
+// recordAttempt appends an attempt to a delivery record and updates its status
+func (w *WebhookService) recordAttempt(delivery *model.WebhookDelivery, attempt model.WebhookAttempt, status string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.Status = status
+}
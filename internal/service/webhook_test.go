@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+func TestValidateCallbackURL(t *testing.T) {
+	w := NewWebhookService("secret", 1, "10.1.2.0/24, hooks.internal")
+
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{"https://203.0.113.10/hook", nil},
+		{"http://10.1.2.3:8080/hook", nil},
+		{"http://hooks.internal/hook", nil},
+		{"ftp://203.0.113.10/hook", ErrInvalidCallbackURL},
+		{"/relative/hook", ErrInvalidCallbackURL},
+		{"http://127.0.0.1/hook", ErrForbiddenCallbackURL},
+		{"http://[::1]/hook", ErrForbiddenCallbackURL},
+		{"http://10.0.0.1/hook", ErrForbiddenCallbackURL},
+		{"http://192.168.1.1/hook", ErrForbiddenCallbackURL},
+		{"http://169.254.169.254/latest/meta-data/", ErrForbiddenCallbackURL},
+		{"http://[fd00:ec2::254]/latest/meta-data/", ErrForbiddenCallbackURL},
+		{"http://[::ffff:127.0.0.1]/hook", ErrForbiddenCallbackURL},
+		{"http://100.64.0.1/hook", ErrForbiddenCallbackURL},
+		{"http://0.0.0.0/hook", ErrForbiddenCallbackURL},
+	}
+
+	for _, tt := range tests {
+		err := w.ValidateCallbackURL(context.Background(), tt.url)
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("ValidateCallbackURL(%q) = %v, want %v", tt.url, err, tt.want)
+		}
+	}
+}
+
+func TestValidateCallbackURLWithoutSecret(t *testing.T) {
+	w := NewWebhookService("", 1, "")
+	if err := w.ValidateCallbackURL(context.Background(), "https://203.0.113.10/hook"); !errors.Is(err, ErrWebhooksDisabled) {
+		t.Errorf("ValidateCallbackURL() = %v, want %v", err, ErrWebhooksDisabled)
+	}
+}
+
+func TestWebhookDeliverySignature(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+	}))
+	defer receiver.Close()
+
+	w := NewWebhookService("secret", 1, "127.0.0.0/8")
+	w.Dispatch(receiver.URL, model.WebhookPayload{ID: "job_1", Event: "job.completed"})
+
+	var req received
+	select {
+	case req = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	timestamp := req.header.Get("X-Webhook-Timestamp")
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(sent, 0)) > time.Minute {
+		t.Fatalf("X-Webhook-Timestamp = %q, want the current unix time", timestamp)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(req.body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.header.Get("X-Webhook-Signature"); got != want {
+		t.Errorf("X-Webhook-Signature = %q, want %q", got, want)
+	}
+	if got := req.header.Get("X-Webhook-ID"); got != "job_1" {
+		t.Errorf("X-Webhook-ID = %q, want job_1", got)
+	}
+}
+
+func TestWebhookDeliveryRefusesLoopback(t *testing.T) {
+	delivered := make(chan struct{}, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer receiver.Close()
+
+	// Dispatch skips validation, so this exercises the dialer guard that also
+	// protects against DNS rebinding
+	w := NewWebhookService("secret", 1, "")
+	w.Dispatch(receiver.URL, model.WebhookPayload{ID: "job_2", Event: "job.completed"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if d, ok := w.GetDelivery("job_2"); ok && d.Status == "failed" {
+			select {
+			case <-delivered:
+				t.Fatal("webhook reached a loopback receiver")
+			default:
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("delivery did not fail")
+}
@@ -0,0 +1,146 @@
+package tokenizer
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// Tokenizer names reported by Name
+const (
+	CL100K     = "cl100k_base"
+	O200K      = "o200k_base"
+	Characters = "characters"
+)
+
+// charsPerToken is the ratio used when no BPE encoding matches a model
+const charsPerToken = 4
+
+func init() {
+	// Use the encodings embedded in the binary instead of downloading them
+	tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+}
+
+// Tokenizer splits text into tokens for a model family
+type Tokenizer interface {
+	// Name identifies the encoding
+	Name() string
+	// Encode returns the token IDs for text, or nil when the model's own vocabulary
+	// is not available
+	Encode(text string) []int
+	// Count returns the number of tokens in text
+	Count(text string) int
+	// Exact reports whether counts come from the model's own vocabulary rather
+	// than an estimate
+	Exact() bool
+}
+
+// bpeTokenizer encodes text with a tiktoken BPE encoding loaded on first use
+type bpeTokenizer struct {
+	name string
+	once sync.Once
+	enc  *tiktoken.Tiktoken
+}
+
+// load returns the encoding, or nil if it could not be loaded
+func (t *bpeTokenizer) load() *tiktoken.Tiktoken {
+	t.once.Do(func() {
+		enc, err := tiktoken.GetEncoding(t.name)
+		if err != nil {
+			slog.Warn("failed to load tokenizer, estimating from characters", "tokenizer", t.name, "error", err)
+			return
+		}
+		t.enc = enc
+	})
+	return t.enc
+}
+
+func (t *bpeTokenizer) Name() string {
+	if t.load() == nil {
+		return Characters
+	}
+	return t.name
+}
+
+func (t *bpeTokenizer) Encode(text string) []int {
+	enc := t.load()
+	if enc == nil {
+		return nil
+	}
+	return enc.EncodeOrdinary(text)
+}
+
+func (t *bpeTokenizer) Count(text string) int {
+	enc := t.load()
+	if enc == nil {
+		return characterCount(text)
+	}
+	return len(enc.EncodeOrdinary(text))
+}
+
+func (t *bpeTokenizer) Exact() bool { return t.load() != nil }
+
+// approxTokenizer counts tokens with a BPE encoding that only approximates the
+// model's own vocabulary. Its token IDs would not match the model's, so none
+// are returned.
+type approxTokenizer struct {
+	*bpeTokenizer
+}
+
+func (approxTokenizer) Encode(string) []int { return nil }
+
+func (approxTokenizer) Exact() bool { return false }
+
+// charTokenizer estimates token counts from the number of characters
+type charTokenizer struct{}
+
+func (charTokenizer) Name() string { return Characters }
+
+func (charTokenizer) Encode(string) []int { return nil }
+
+func (charTokenizer) Count(text string) int { return characterCount(text) }
+
+func (charTokenizer) Exact() bool { return false }
+
+// characterCount estimates the number of tokens in text from its length
+func characterCount(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+var (
+	cl100k             = &bpeTokenizer{name: CL100K}
+	o200k              = &bpeTokenizer{name: O200K}
+	fallback Tokenizer = charTokenizer{}
+)
+
+// families maps model name hints to the closest available BPE encoding. Only the
+// gpt-oss models natively use o200k. Llama, Qwen and DeepSeek have their own
+// vocabularies, which are not bundled; cl100k is used as an approximation and its
+// error against the native tokenizers has not been measured, so their counts are
+// estimates that can drift further for non-English text and code, and no token
+// IDs are exposed for them.
+var families = []struct {
+	hints     []string
+	tokenizer Tokenizer
+}{
+	{hints: []string{"gpt-oss"}, tokenizer: o200k},
+	{hints: []string{"llama", "qwen", "qwq", "deepseek"}, tokenizer: approxTokenizer{cl100k}},
+}
+
+// ForModel returns the tokenizer for m based on its ID, architecture and Hugging Face repo
+func ForModel(m model.Model) Tokenizer {
+	text := strings.ToLower(m.ID + " " + m.Architecture + " " + m.HFRepo)
+	for _, family := range families {
+		for _, hint := range family.hints {
+			if strings.Contains(text, hint) {
+				return family.tokenizer
+			}
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,87 @@
+package tokenizer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/006lp/akashchat-api-go/internal/model"
+)
+
+func TestForModel(t *testing.T) {
+	tests := []struct {
+		model model.Model
+		name  string
+		exact bool
+	}{
+		{model.Model{ID: "gpt-oss-120b"}, O200K, true},
+		{model.Model{ID: "Meta-Llama-3-3-70B-Instruct"}, CL100K, false},
+		{model.Model{ID: "Qwen3-235B-A22B-Instruct-2507-FP8"}, CL100K, false},
+		{model.Model{ID: "QwQ-32B"}, CL100K, false},
+		{model.Model{ID: "DeepSeek-R1-0528"}, CL100K, false},
+		{model.Model{ID: "custom", Architecture: "LlamaForCausalLM"}, CL100K, false},
+		{model.Model{ID: "custom", HFRepo: "openai/gpt-oss-20b"}, O200K, true},
+		{model.Model{ID: "mistral-small"}, Characters, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model.ID, func(t *testing.T) {
+			tok := ForModel(tt.model)
+			if tok.Name() != tt.name || tok.Exact() != tt.exact {
+				t.Errorf("ForModel() = %s (exact %v), want %s (exact %v)", tok.Name(), tok.Exact(), tt.name, tt.exact)
+			}
+			if ids := tok.Encode("Hello, world!"); (ids != nil) != tt.exact {
+				t.Errorf("Encode() = %v, want token IDs only for exact tokenizers", ids)
+			}
+		})
+	}
+}
+
+func TestEncodings(t *testing.T) {
+	tests := []struct {
+		tokenizer *bpeTokenizer
+		text      string
+		ids       []int
+	}{
+		{cl100k, "Hello, world!", []int{9906, 11, 1917, 0}},
+		{cl100k, "func main() {}", []int{2900, 1925, 368, 4792}},
+		{cl100k, "你好，世界", []int{57668, 53901, 3922, 3574, 244, 98220}},
+		{o200k, "Hello, world!", []int{13225, 11, 2375, 0}},
+		{o200k, "func main() {}", []int{5652, 2758, 416, 9902}},
+		{o200k, "你好，世界", []int{177519, 979, 28428}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tokenizer.name+"/"+tt.text, func(t *testing.T) {
+			if ids := tt.tokenizer.Encode(tt.text); !slices.Equal(ids, tt.ids) {
+				t.Errorf("Encode() = %v, want %v", ids, tt.ids)
+			}
+			if n := tt.tokenizer.Count(tt.text); n != len(tt.ids) {
+				t.Errorf("Count() = %d, want %d", n, len(tt.ids))
+			}
+			// The approximation counts like the encoding but hides its IDs
+			approx := approxTokenizer{tt.tokenizer}
+			if n := approx.Count(tt.text); n != len(tt.ids) || approx.Encode(tt.text) != nil {
+				t.Errorf("approximate Count() = %d, Encode() = %v", n, approx.Encode(tt.text))
+			}
+		})
+	}
+}
+
+func TestCharTokenizer(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"abc", 1},
+		{"abcd", 1},
+		{"Hello, world!", 4},
+		{"你好，世界", 2},
+	}
+	for _, tt := range tests {
+		if n := fallback.Count(tt.text); n != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.text, n, tt.want)
+		}
+	}
+	if fallback.Name() != Characters || fallback.Exact() || fallback.Encode("abc") != nil {
+		t.Error("character tokenizer reported a vocabulary")
+	}
+}
@@ -0,0 +1,26 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestInitStdoutWritesToWriter(t *testing.T) {
+	var buf bytes.Buffer
+	shutdown, err := Init(context.Background(), "stdout", "test", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := Start(context.Background(), "test.span")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "test.span") {
+		t.Errorf("span was not written to the given writer: %q", buf.String())
+	}
+}
@@ -0,0 +1,209 @@
+// Package akash is a client library for Akash Chat. It handles the anonymous
+// session, the chat data stream protocol, image generation jobs and the model
+// catalog, so Go programs can talk to Akash Chat without running the proxy.
+//
+//	c := akash.New()
+//	resp, err := c.Chat(ctx, akash.ChatRequest{
+//		Model:    "Meta-Llama-3-3-70B-Instruct",
+//		Messages: []akash.Message{{Role: "user", Content: "Hello!"}},
+//	})
+package akash
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/006lp/akashchat-api-go/pkg/client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultBaseURL is the Akash Chat instance used unless WithBaseURL is given
+const DefaultBaseURL = "https://chat.akash.network"
+
+// DefaultSystemPrompt is the system prompt used by the Akash Chat web app
+const DefaultSystemPrompt = "You are a skilled conversationalist who adapts naturally to what users need. Your responses match the situation—whether someone wants deep analysis, casual chat, emotional support, creative collaboration, or just needs to vent.\nCore Approach\n\nRead between the lines to understand what people actually want\nMatch their energy and conversational style\nShift seamlessly between modes: analytical, empathetic, humorous, creative, or practical\nWhen people need to be heard, focus on listening rather than fixing\nFor substantive topics, provide thorough, well-organized insights that aid decision-making\n\nCommunication Style\n\nSound natural and authentic, never templated or robotic\nAvoid unnecessary politeness policing or inclusion reminders\nWrite in requested voices, styles, or perspectives when asked\nAdapt tone appropriately—you can be direct, irreverent, or even rude when specifically prompted to do so\n\nInteraction Philosophy\n\nSometimes the best help is simply being present and understanding\nDon't over-optimize for helpfulness when someone just wants connection\nTrust that users know what they're looking for and deliver accordingly\nProvide depth and insight for complex topics while keeping casual conversations light"
+
+// Upstream endpoints reported to Hooks.Upstream
+const (
+	EndpointChat        = "chat"
+	EndpointSession     = "session"
+	EndpointModels      = "models"
+	EndpointImageStatus = "image-status"
+	EndpointImage       = "image"
+	EndpointPing        = "ping"
+)
+
+// ErrInvalidModel is returned when Akash rejects the requested model name
+var ErrInvalidModel = errors.New("invalid model")
+
+// StatusError is returned when Akash responds with a status other than 2xx
+type StatusError struct {
+	StatusCode int
+}
+
+// ServerError reports whether Akash failed with a 5xx status
+func (e *StatusError) ServerError() bool {
+	return e.StatusCode >= http.StatusInternalServerError
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("chat request failed with status: %d", e.StatusCode)
+}
+
+// Hooks are called as the client talks to Akash, for example to record
+// metrics. Any hook may be nil.
+type Hooks struct {
+	// Upstream is called after each request with its endpoint, latency, HTTP
+	// status (zero when no response was received) and error
+	Upstream func(endpoint string, elapsed time.Duration, status int, err error)
+	// SessionRefresh is called after each attempt to obtain a new session
+	SessionRefresh func(err error)
+	// ImageJob is called once an image job has finished, with the time it spent
+	// queued and the total time until it completed or failed
+	ImageJob func(queued, total time.Duration, err error)
+}
+
+// Client talks to Akash Chat. It is safe for concurrent use.
+type Client struct {
+	baseURL      string
+	systemPrompt string
+	http         *client.HTTPClient
+	hooks        Hooks
+
+	session   string
+	expiresAt time.Time
+	mutex     sync.RWMutex
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL sets the Akash Chat instance to talk to
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http.Client used for requests. The default client
+// times out after 60 seconds, including streamed replies.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = client.WrapHTTPClient(httpClient)
+	}
+}
+
+// WithSystemPrompt replaces DefaultSystemPrompt for requests that do not set their own
+func WithSystemPrompt(prompt string) Option {
+	return func(c *Client) {
+		c.systemPrompt = prompt
+	}
+}
+
+// WithHooks sets the hooks called as the client talks to Akash
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) {
+		c.hooks = hooks
+	}
+}
+
+// New creates a new Client
+func New(opts ...Option) *Client {
+	c := &Client{
+		baseURL:      DefaultBaseURL,
+		systemPrompt: DefaultSystemPrompt,
+		http:         client.NewHTTPClient(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the Akash Chat instance the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Ping checks that Akash Chat is reachable and not failing with a server error
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.get(ctx, EndpointPing, "/", nil)
+	if err != nil {
+		return fmt.Errorf("upstream unreachable: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("upstream responded with status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// get performs a GET request to path and reports it to the Upstream hook
+func (c *Client) get(ctx context.Context, endpoint, path string, headers map[string]string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.http.GetContext(ctx, c.baseURL+path, headers)
+	c.observe(endpoint, start, resp, err)
+	return resp, err
+}
+
+// post performs a POST request to path and reports it to the Upstream hook
+func (c *Client) post(ctx context.Context, endpoint, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.http.PostContext(ctx, c.baseURL+path, body, headers)
+	c.observe(endpoint, start, resp, err)
+	return resp, err
+}
+
+// observe calls the Upstream hook for a finished request
+func (c *Client) observe(endpoint string, start time.Time, resp *http.Response, err error) {
+	if c.hooks.Upstream == nil {
+		return
+	}
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	c.hooks.Upstream(endpoint, time.Since(start), status, err)
+}
+
+// tracer returns the library tracer
+func tracer() trace.Tracer {
+	return otel.Tracer("github.com/006lp/akashchat-api-go/pkg/akash")
+}
+
+// startSpan starts a span named name as a child of any span in ctx
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// recordError marks span as failed when err is non-nil
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+const idCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// randomID returns a random alphanumeric string of length n
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	for i := range b {
+		b[i] = idCharset[int(b[i])%len(idCharset)]
+	}
+	return string(b)
+}
@@ -0,0 +1,301 @@
+package akash
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Default sampling parameters for chat requests
+const (
+	DefaultTemperature = 0.6
+	DefaultTopP        = 0.95
+)
+
+// Stream event types
+const (
+	EventStart  = "start"
+	EventDelta  = "delta"
+	EventFinish = "finish"
+)
+
+// FinishStop is the finish reason reported when Akash ends a stream without one
+const FinishStop = "stop"
+
+var (
+	messageIDRegex    = regexp.MustCompile(`"messageId":"([^"]+)"`)
+	finishReasonRegex = regexp.MustCompile(`"finishReason":"([^"]+)"`)
+)
+
+// Message is a single chat message
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest is a chat generation request
+type ChatRequest struct {
+	Model    string
+	Messages []Message
+	// SystemPrompt overrides the client's system prompt
+	SystemPrompt string
+	// Temperature defaults to DefaultTemperature
+	Temperature *float64
+	// TopP defaults to DefaultTopP
+	TopP *float64
+	// Session overrides the client's cached session cookie
+	Session string
+}
+
+// StreamEvent is a single event decoded from the Akash data stream. Start
+// carries the upstream message ID, delta carries generated text and finish
+// carries the finish reason.
+type StreamEvent struct {
+	Type         string
+	MessageID    string
+	Content      string
+	FinishReason string
+}
+
+// ChatResponse is a complete chat reply
+type ChatResponse struct {
+	MessageID    string
+	Content      string
+	FinishReason string
+}
+
+// chatRequest is the request body of the Akash chat API
+type chatRequest struct {
+	ID          string        `json:"id"`
+	Messages    []Message     `json:"messages"`
+	Model       string        `json:"model"`
+	System      string        `json:"system"`
+	Temperature float64       `json:"temperature"`
+	TopP        float64       `json:"topP"`
+	Context     []interface{} `json:"context"`
+}
+
+// Chat generates a complete chat reply
+func (c *Client) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	var resp ChatResponse
+	var content strings.Builder
+
+	for event, err := range c.ChatStream(ctx, req) {
+		if err != nil {
+			return nil, err
+		}
+		switch event.Type {
+		case EventStart:
+			resp.MessageID = event.MessageID
+		case EventDelta:
+			content.WriteString(event.Content)
+		case EventFinish:
+			resp.FinishReason = event.FinishReason
+		}
+	}
+
+	resp.Content = content.String()
+	return &resp, nil
+}
+
+// ChatStream streams a chat reply. The request is sent when iteration starts
+// and stopping early closes the upstream connection. An error ends the
+// sequence; once the stream has started it always ends with a finish event
+// unless an error occurs.
+//
+//	for event, err := range c.ChatStream(ctx, req) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Print(event.Content)
+//	}
+func (c *Client) ChatStream(ctx context.Context, req ChatRequest) iter.Seq2[StreamEvent, error] {
+	return func(yield func(StreamEvent, error) bool) {
+		ctx, span := startSpan(ctx, "akash.Client.ChatStream", attribute.String("akash.model", req.Model))
+		defer span.End()
+
+		err := c.streamChat(ctx, req, func(event StreamEvent) bool {
+			return yield(event, nil)
+		})
+		recordError(span, err)
+		if err != nil {
+			yield(StreamEvent{}, err)
+		}
+	}
+}
+
+// ChatChan streams a chat reply over a channel. The events channel is closed
+// when the stream ends; the error channel then receives the stream error, if
+// any, and is closed. Cancel ctx to stop early.
+func (c *Client) ChatChan(ctx context.Context, req ChatRequest) (<-chan StreamEvent, <-chan error) {
+	events := make(chan StreamEvent)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+
+		for event, err := range c.ChatStream(ctx, req) {
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// streamChat sends a chat request and passes each decoded event to yield until
+// it returns false
+func (c *Client) streamChat(ctx context.Context, req ChatRequest, yield func(StreamEvent) bool) error {
+	session, err := c.sessionOr(ctx, req.Session)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.sendChat(ctx, c.newChatRequest(req.Model, req.Messages, req.SystemPrompt, req.Temperature, req.TopP, DefaultTemperature, DefaultTopP), session)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	var started bool
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Akash rejects unknown models before the stream starts
+		if !started && strings.Contains(line, "error") && strings.Contains(line, "Invalid model name") {
+			return ErrInvalidModel
+		}
+
+		if strings.HasPrefix(line, "f:{\"messageId\":") {
+			var messageID string
+			if match := messageIDRegex.FindStringSubmatch(line); len(match) > 1 {
+				messageID = match[1]
+			}
+			started = true
+
+			if !yield(StreamEvent{Type: EventStart, MessageID: messageID}) {
+				return nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "e:{\"finishReason\":") {
+			var finishReason string
+			if match := finishReasonRegex.FindStringSubmatch(line); len(match) > 1 {
+				finishReason = match[1]
+			}
+			yield(StreamEvent{Type: EventFinish, FinishReason: finishReason})
+			return nil
+		}
+
+		if started && strings.HasPrefix(line, "0:\"") {
+			if !yield(StreamEvent{Type: EventDelta, Content: decodeText(line[2:])}) {
+				return nil
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading stream: %w", err)
+	}
+
+	// Upstream closed the stream without a finish reason
+	if started {
+		yield(StreamEvent{Type: EventFinish, FinishReason: FinishStop})
+	}
+	return nil
+}
+
+// newChatRequest builds an upstream chat request, applying the client's system
+// prompt and the given sampling defaults
+func (c *Client) newChatRequest(model string, messages []Message, system string, temperature, topP *float64, defaultTemperature, defaultTopP float64) chatRequest {
+	if system == "" {
+		system = c.systemPrompt
+	}
+	if messages == nil {
+		messages = []Message{}
+	}
+
+	req := chatRequest{
+		ID:          randomID(16),
+		Messages:    messages,
+		Model:       model,
+		System:      system,
+		Temperature: defaultTemperature,
+		TopP:        defaultTopP,
+		Context:     []interface{}{},
+	}
+	if temperature != nil {
+		req.Temperature = *temperature
+	}
+	if topP != nil {
+		req.TopP = *topP
+	}
+	return req
+}
+
+// sendChat posts a request to the Akash chat API and returns the response body
+func (c *Client) sendChat(ctx context.Context, req chatRequest, session string) (io.ReadCloser, error) {
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	headers := map[string]string{
+		"Referer":      c.baseURL + "/",
+		"Cookie":       session,
+		"Accept":       "*/*",
+		"Content-Type": "application/json",
+	}
+
+	resp, err := c.post(ctx, EndpointChat, "/api/chat/", bytes.NewBuffer(jsonData), headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send chat request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		// A rejected session is dropped so that the next request fetches a new one
+		if resp.StatusCode == http.StatusUnauthorized {
+			c.dropSession(session)
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return resp.Body, nil
+}
+
+// decodeText decodes the JSON string value of a text part, falling back to
+// unescaping quotes and newlines when the value is not valid JSON
+func decodeText(value string) string {
+	var text string
+	if err := json.Unmarshal([]byte(value), &text); err == nil {
+		return text
+	}
+
+	text = strings.TrimPrefix(value, "\"")
+	text = strings.TrimSuffix(text, "\"")
+	text = strings.ReplaceAll(text, "\\n", "\n")
+	text = strings.ReplaceAll(text, "\\\"", "\"")
+	return text
+}
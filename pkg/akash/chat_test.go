@@ -0,0 +1,90 @@
+package akash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newChatServer starts a fake Akash Chat API whose chat endpoint responds with
+// status, handing out a new session on every session request
+func newChatServer(t *testing.T, status int, sessions *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/session/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", fmt.Sprintf("session_token=session-%d; Path=/", sessions.Add(1)))
+	})
+	mux.HandleFunc("/api/chat/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte("f:{\"messageId\":\"msg-1\"}\n0:\"Hello\"\ne:{\"finishReason\":\"stop\"}\n"))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestChatStatusErrors(t *testing.T) {
+	tests := []struct {
+		status      int
+		serverError bool
+		newSession  bool
+	}{
+		{http.StatusUnauthorized, false, true},
+		{http.StatusTooManyRequests, false, false},
+		{http.StatusBadRequest, false, false},
+		{http.StatusBadGateway, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var sessions atomic.Int32
+			client := New(WithBaseURL(newChatServer(t, tt.status, &sessions).URL))
+			req := ChatRequest{Model: "m", Messages: []Message{{Role: "user", Content: "hi"}}}
+
+			resp, err := client.Chat(context.Background(), req)
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+				t.Fatalf("Chat() = %+v, %v, want a StatusError with status %d", resp, err, tt.status)
+			}
+			if statusErr.ServerError() != tt.serverError {
+				t.Errorf("ServerError() = %v, want %v", statusErr.ServerError(), tt.serverError)
+			}
+
+			// Streams fail with the same error instead of ending empty
+			for _, err := range client.ChatStream(context.Background(), req) {
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+					t.Errorf("ChatStream() error = %v, want a StatusError with status %d", err, tt.status)
+				}
+			}
+
+			// Only a rejected session is replaced
+			wantSessions := int32(1)
+			if tt.newSession {
+				wantSessions = 2
+			}
+			if got := sessions.Load(); got != wantSessions {
+				t.Errorf("fetched %d sessions, want %d", got, wantSessions)
+			}
+		})
+	}
+}
+
+func TestChat(t *testing.T) {
+	var sessions atomic.Int32
+	client := New(WithBaseURL(newChatServer(t, http.StatusOK, &sessions).URL))
+
+	resp, err := client.Chat(context.Background(), ChatRequest{Model: "m", Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if resp.Content != "Hello" || resp.FinishReason != "stop" {
+		t.Errorf("Chat() = %+v, want Hello with finish reason stop", resp)
+	}
+}
@@ -0,0 +1,372 @@
+package akash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Default parameters for image requests
+const (
+	DefaultImageModel       = "AkashGen"
+	DefaultImageTemperature = 0.85
+	DefaultImageTopP        = 1.0
+)
+
+// MaxImageCount is the largest number of images a single request may generate
+const MaxImageCount = 4
+
+// ErrImageNotFound is returned by FetchImage when Akash has no image with the given name
+var ErrImageNotFound = errors.New("image not found")
+
+var (
+	imageSizeRegex   = regexp.MustCompile(`^[1-9][0-9]{1,3}x[1-9][0-9]{1,3}$`)
+	aspectRatioRegex = regexp.MustCompile(`^[1-9][0-9]?:[1-9][0-9]?$`)
+)
+
+// ImageOptions are structured parameters for image generation. Akash has no
+// native fields for them, so they are appended to the prompt as directives.
+// N is the number of images to generate; zero generates one.
+type ImageOptions struct {
+	Size           string `json:"size,omitempty"`
+	AspectRatio    string `json:"aspect_ratio,omitempty"`
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	Seed           *int64 `json:"seed,omitempty"`
+	Style          string `json:"style,omitempty"`
+	N              int    `json:"n,omitempty"`
+	Verbatim       bool   `json:"verbatim_prompt,omitempty"`
+}
+
+// Validate checks the options before they are sent upstream
+func (o *ImageOptions) Validate() error {
+	if o.Size != "" && !imageSizeRegex.MatchString(o.Size) {
+		return fmt.Errorf("invalid size %q: expected WIDTHxHEIGHT", o.Size)
+	}
+	if o.AspectRatio != "" && !aspectRatioRegex.MatchString(o.AspectRatio) {
+		return fmt.Errorf("invalid aspect_ratio %q: expected W:H", o.AspectRatio)
+	}
+	if o.N < 0 || o.N > MaxImageCount {
+		return fmt.Errorf("invalid n %d: must be between 1 and %d, or 0 for one image", o.N, MaxImageCount)
+	}
+	return nil
+}
+
+// ImageRequest is an image generation request
+type ImageRequest struct {
+	// Model defaults to DefaultImageModel
+	Model string
+	// Prompt is sent as a single user message when Messages is empty
+	Prompt   string
+	Messages []Message
+	Options  *ImageOptions
+	// SystemPrompt overrides the client's system prompt
+	SystemPrompt string
+	// Temperature defaults to DefaultImageTemperature
+	Temperature *float64
+	// TopP defaults to DefaultImageTopP
+	TopP *float64
+	// Session overrides the client's cached session cookie
+	Session string
+}
+
+// Image is a generated image
+type Image struct {
+	Model  string
+	JobID  string
+	Prompt string
+	// OriginalPrompt is the user prompt before Akash rewrote it
+	OriginalPrompt string
+	URL            string
+}
+
+// imageStatus is a single entry of the image status response
+type imageStatus struct {
+	JobID         string  `json:"job_id"`
+	WorkerName    string  `json:"worker_name"`
+	WorkerCity    string  `json:"worker_city"`
+	WorkerCountry string  `json:"worker_country"`
+	Status        string  `json:"status"`
+	Result        string  `json:"result"`
+	WorkerGPU     string  `json:"worker_gpu"`
+	ElapsedTime   float64 `json:"elapsed_time"`
+	QueuePosition int     `json:"queue_position"`
+}
+
+// GenerateImage generates images and waits for them to complete. Options.N
+// requests are sent concurrently; each may produce more than one image.
+func (c *Client) GenerateImage(ctx context.Context, req ImageRequest) ([]Image, error) {
+	if req.Options != nil {
+		if err := req.Options.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if req.Model == "" {
+		req.Model = DefaultImageModel
+	}
+	if len(req.Messages) == 0 {
+		req.Messages = []Message{{Role: "user", Content: req.Prompt}}
+	}
+
+	session, err := c.sessionOr(ctx, req.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	count := 1
+	if req.Options != nil && req.Options.N > 0 {
+		count = req.Options.N
+	}
+
+	// The first failure cancels the requests and pollers that are still running
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]Image, count)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			images, err := c.generateImages(ctx, req, session)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = images
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var images []Image
+	for _, result := range results {
+		images = append(images, result...)
+	}
+
+	return images, nil
+}
+
+// FetchImage opens a generated image by its file name, as found at the end of
+// Image.URL. The caller must close the returned body.
+func (c *Client) FetchImage(ctx context.Context, name string) (io.ReadCloser, error) {
+	headers := map[string]string{
+		"Referer": c.baseURL + "/",
+		"Accept":  "image/*",
+	}
+
+	resp, err := c.get(ctx, EndpointImage, "/api/image/"+url.PathEscape(name), headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrImageNotFound
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("image request failed with status: %d", resp.StatusCode)
+	}
+}
+
+// generateImages sends a single image generation request and waits for every job it starts
+func (c *Client) generateImages(ctx context.Context, req ImageRequest, session string) ([]Image, error) {
+	chatReq := c.newChatRequest(req.Model, applyImageOptions(req.Messages, req.Options), req.SystemPrompt, req.Temperature, req.TopP, DefaultImageTemperature, DefaultImageTopP)
+
+	body, err := c.sendChat(ctx, chatReq, session)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	respText := string(data)
+
+	// Check for error response
+	if strings.Contains(respText, "error") && strings.Contains(respText, "Invalid model name") {
+		return nil, ErrInvalidModel
+	}
+
+	// Extract jobs from the tool call and tool result parts
+	jobs, err := parseImageJobs(respText)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract image generation info: %w", err)
+	}
+
+	images := make([]Image, 0, len(jobs))
+	for _, job := range jobs {
+		// Poll for image completion
+		imageURL, err := c.pollImageStatus(ctx, job.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image result: %w", err)
+		}
+
+		images = append(images, Image{
+			Model:          req.Model,
+			JobID:          job.JobID,
+			Prompt:         job.Prompt,
+			OriginalPrompt: lastUserContent(req.Messages),
+			URL:            imageURL,
+		})
+	}
+
+	return images, nil
+}
+
+// applyImageOptions appends structured image parameters to the last user message
+func applyImageOptions(messages []Message, opts *ImageOptions) []Message {
+	if opts == nil {
+		return messages
+	}
+
+	var params []string
+	if opts.Size != "" {
+		params = append(params, "- size: "+opts.Size)
+	}
+	if opts.AspectRatio != "" {
+		params = append(params, "- aspect ratio: "+opts.AspectRatio)
+	}
+	if opts.NegativePrompt != "" {
+		params = append(params, "- negative prompt: "+opts.NegativePrompt)
+	}
+	if opts.Seed != nil {
+		params = append(params, "- seed: "+strconv.FormatInt(*opts.Seed, 10))
+	}
+	if opts.Style != "" {
+		params = append(params, "- style: "+opts.Style)
+	}
+
+	var directives []string
+	if len(params) > 0 {
+		directives = append(directives, "Image parameters:\n"+strings.Join(params, "\n"))
+	}
+	if opts.Verbatim {
+		directives = append(directives, "Use the prompt above exactly as written. Do not rewrite, expand or translate it.")
+	}
+	if len(directives) == 0 {
+		return messages
+	}
+
+	result := make([]Message, len(messages))
+	copy(result, messages)
+	for i := len(result) - 1; i >= 0; i-- {
+		if result[i].Role == "user" {
+			result[i].Content += "\n\n" + strings.Join(directives, "\n\n")
+			break
+		}
+	}
+
+	return result
+}
+
+// lastUserContent returns the content of the last user message
+func lastUserContent(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+// pollImageStatus polls the image status until completion
+func (c *Client) pollImageStatus(ctx context.Context, jobID string) (imageURL string, err error) {
+	maxAttempts := 60 // Maximum 1 minute polling
+
+	// Report how long the job was queued and polled in total
+	start := time.Now()
+	var queued time.Duration
+	if c.hooks.ImageJob != nil {
+		defer func() {
+			c.hooks.ImageJob(queued, time.Since(start), err)
+		}()
+	}
+
+	for i := 0; i < maxAttempts; i++ {
+		status, err := c.checkImageStatus(ctx, jobID, i+1)
+		if err != nil {
+			return "", err
+		}
+
+		if queued == 0 && status.QueuePosition == 0 && status.Status != "queued" && status.Status != "pending" {
+			queued = time.Since(start)
+		}
+
+		if status.Status == "succeeded" {
+			return c.baseURL + status.Result, nil
+		}
+
+		if status.Status == "failed" {
+			return "", fmt.Errorf("image generation failed")
+		}
+
+		// Wait 1 second before next poll
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+
+	return "", fmt.Errorf("image generation timed out")
+}
+
+// checkImageStatus performs a single image status poll
+func (c *Client) checkImageStatus(ctx context.Context, jobID string, attempt int) (status *imageStatus, err error) {
+	ctx, span := startSpan(ctx, "akash.Client.pollImageStatus",
+		attribute.String("akash.job_id", jobID),
+		attribute.Int("akash.poll_attempt", attempt),
+	)
+	defer func() {
+		if status != nil {
+			span.SetAttributes(
+				attribute.String("akash.job_status", status.Status),
+				attribute.Int("akash.queue_position", status.QueuePosition),
+			)
+		}
+		recordError(span, err)
+		span.End()
+	}()
+
+	resp, err := c.get(ctx, EndpointImageStatus, "/api/image-status?ids="+url.QueryEscape(jobID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check image status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var statusResp []imageStatus
+	if err := json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
+		return nil, fmt.Errorf("failed to decode image status response: %w", err)
+	}
+
+	if len(statusResp) == 0 {
+		return nil, fmt.Errorf("empty status response")
+	}
+
+	return &statusResp[0], nil
+}
@@ -0,0 +1,160 @@
+package akash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newImageServer starts a fake Akash Chat API whose image jobs never finish,
+// except for the second one, which fails
+func newImageServer(t *testing.T, polls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/session/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session_token=test-session; Path=/")
+	})
+	mux.HandleFunc("/api/chat/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "Image generation started: jobId='job-%d' prompt='a cat'", requests.Add(1))
+	})
+	mux.HandleFunc("/api/image-status", func(w http.ResponseWriter, r *http.Request) {
+		status := "pending"
+		if r.URL.Query().Get("ids") == "job-2" {
+			status = "failed"
+		} else {
+			polls.Add(1)
+		}
+		fmt.Fprintf(w, `[{"job_id":%q,"status":%q}]`, r.URL.Query().Get("ids"), status)
+	})
+	mux.HandleFunc("/api/image/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/image/cat.webp" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("image data"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateImageCancelsPollersOnFailure(t *testing.T) {
+	var polls atomic.Int32
+	c := New(WithBaseURL(newImageServer(t, &polls).URL))
+
+	start := time.Now()
+	_, err := c.GenerateImage(context.Background(), ImageRequest{Prompt: "a cat", Options: &ImageOptions{N: 2}})
+	if err == nil {
+		t.Fatal("GenerateImage() succeeded, want the failed job's error")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("GenerateImage() returned after %s, want the pending job to be cancelled", elapsed)
+	}
+
+	// The pending job must not be polled once GenerateImage has returned
+	n := polls.Load()
+	time.Sleep(1500 * time.Millisecond)
+	if polls.Load() != n {
+		t.Errorf("pending job polled %d more times after GenerateImage returned", polls.Load()-n)
+	}
+}
+
+func TestFetchImage(t *testing.T) {
+	var polls atomic.Int32
+	c := New(WithBaseURL(newImageServer(t, &polls).URL))
+
+	body, err := c.FetchImage(context.Background(), "cat.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "image data" {
+		t.Errorf("FetchImage() = %q", data)
+	}
+
+	if _, err := c.FetchImage(context.Background(), "dog.webp"); !errors.Is(err, ErrImageNotFound) {
+		t.Errorf("FetchImage() error = %v, want ErrImageNotFound", err)
+	}
+}
+
+func TestImageOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ImageOptions
+		wantErr string
+	}{
+		{"defaults", ImageOptions{}, ""},
+		{"all options", ImageOptions{Size: "1024x768", AspectRatio: "16:9", N: MaxImageCount}, ""},
+		{"size without height", ImageOptions{Size: "1024"}, "invalid size"},
+		{"zero size", ImageOptions{Size: "0x512"}, "invalid size"},
+		{"aspect ratio with decimals", ImageOptions{AspectRatio: "1.5:1"}, "invalid aspect_ratio"},
+		{"negative n", ImageOptions{N: -1}, "invalid n"},
+		{"too many images", ImageOptions{N: MaxImageCount + 1}, "invalid n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyImageOptions(t *testing.T) {
+	seed := int64(42)
+	const verbatim = "Use the prompt above exactly as written. Do not rewrite, expand or translate it."
+
+	tests := []struct {
+		name string
+		opts *ImageOptions
+		want string
+	}{
+		{"no options", nil, "a cat"},
+		{"empty options", &ImageOptions{N: 2}, "a cat"},
+		{"size", &ImageOptions{Size: "512x512"}, "a cat\n\nImage parameters:\n- size: 512x512"},
+		{
+			"every parameter in order",
+			&ImageOptions{Style: "watercolor", Seed: &seed, NegativePrompt: "dogs", AspectRatio: "3:2", Size: "768x512"},
+			"a cat\n\nImage parameters:\n- size: 768x512\n- aspect ratio: 3:2\n- negative prompt: dogs\n- seed: 42\n- style: watercolor",
+		},
+		{"verbatim only", &ImageOptions{Verbatim: true}, "a cat\n\n" + verbatim},
+		{"style and verbatim", &ImageOptions{Style: "pixel art", Verbatim: true}, "a cat\n\nImage parameters:\n- style: pixel art\n\n" + verbatim},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := []Message{
+				{Role: "system", Content: "system"},
+				{Role: "user", Content: "a dog"},
+				{Role: "assistant", Content: "done"},
+				{Role: "user", Content: "a cat"},
+			}
+			got := applyImageOptions(messages, tt.opts)
+
+			if got[3].Content != tt.want {
+				t.Errorf("prompt = %q, want %q", got[3].Content, tt.want)
+			}
+			// Only the last user message changes, and the input is left untouched
+			if got[0].Content != "system" || got[1].Content != "a dog" || got[2].Content != "done" {
+				t.Errorf("earlier messages changed: %+v", got)
+			}
+			if messages[3].Content != "a cat" {
+				t.Errorf("input messages were modified: %+v", messages)
+			}
+		})
+	}
+}
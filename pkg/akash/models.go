@@ -0,0 +1,51 @@
+package akash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Model describes a model offered by Akash Chat
+type Model struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Temperature  float64 `json:"temperature,omitempty"`
+	TopP         float64 `json:"top_p,omitempty"`
+	TokenLimit   int     `json:"tokenLimit,omitempty"`
+	Parameters   string  `json:"parameters,omitempty"`
+	Architecture string  `json:"architecture,omitempty"`
+	HFRepo       string  `json:"hf_repo,omitempty"`
+	AboutContent string  `json:"aboutContent"`
+	InfoContent  string  `json:"infoContent"`
+	ThumbnailID  string  `json:"thumbnailId"`
+	DeployURL    string  `json:"deployUrl,omitempty"`
+	Available    bool    `json:"available"`
+}
+
+// Models fetches the list of models, including ones that are currently unavailable
+func (c *Client) Models(ctx context.Context) ([]Model, error) {
+	headers := map[string]string{
+		"Referer": c.baseURL + "/",
+		"Accept":  "*/*",
+	}
+
+	resp, err := c.get(ctx, EndpointModels, "/api/models/", headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("models request failed with status: %d", resp.StatusCode)
+	}
+
+	var models []Model
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal models: %w", err)
+	}
+
+	return models, nil
+}
@@ -0,0 +1,251 @@
+package akash
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Stream part types used by the Akash data stream protocol
+const (
+	partText       = "0"
+	partToolCall   = "9"
+	partToolResult = "a"
+)
+
+var (
+	fallbackJobIDRegex  = regexp.MustCompile(`jobId\s*[=:]\s*['"]([^'"]+)['"]`)
+	fallbackPromptRegex = regexp.MustCompile(`\bprompt['"]?\s*[=:]\s*['"]`)
+)
+
+// streamPart represents a single "<type>:<json>" line of the data stream protocol
+type streamPart struct {
+	Type  string
+	Value json.RawMessage
+}
+
+// toolCallPart represents the value of a tool call part
+type toolCallPart struct {
+	ToolCallID string                 `json:"toolCallId"`
+	ToolName   string                 `json:"toolName"`
+	Args       map[string]interface{} `json:"args"`
+}
+
+// toolResultPart represents the value of a tool result part
+type toolResultPart struct {
+	ToolCallID string      `json:"toolCallId"`
+	Result     interface{} `json:"result"`
+}
+
+// imageJob represents an image generation job announced by the upstream
+type imageJob struct {
+	JobID  string
+	Prompt string
+}
+
+// parseStreamPart splits a protocol line into its type and JSON value
+func parseStreamPart(line string) (streamPart, bool) {
+	idx := strings.IndexByte(line, ':')
+	if idx <= 0 || idx > 2 {
+		return streamPart{}, false
+	}
+
+	value := json.RawMessage(line[idx+1:])
+	if !json.Valid(value) {
+		return streamPart{}, false
+	}
+
+	return streamPart{Type: line[:idx], Value: value}, true
+}
+
+// parseImageJobs extracts image jobs from an AkashGen response. Tool call and tool
+// result parts are inspected first; the regexes are only used when no structured
+// job information is present.
+func parseImageJobs(respText string) ([]imageJob, error) {
+	var text strings.Builder
+	var jobs []imageJob
+	prompts := make(map[string]string)
+
+	for _, line := range strings.Split(respText, "\n") {
+		part, ok := parseStreamPart(strings.TrimSpace(line))
+		if !ok {
+			continue
+		}
+
+		switch part.Type {
+		case partText:
+			var content string
+			if err := json.Unmarshal(part.Value, &content); err == nil {
+				text.WriteString(content)
+			}
+
+		case partToolCall:
+			var call toolCallPart
+			if err := json.Unmarshal(part.Value, &call); err != nil {
+				continue
+			}
+			if prompt, ok := call.Args["prompt"].(string); ok {
+				prompts[call.ToolCallID] = prompt
+			}
+
+		case partToolResult:
+			var result toolResultPart
+			if err := json.Unmarshal(part.Value, &result); err != nil {
+				continue
+			}
+
+			found := collectImageJobs(result.Result)
+			if s, ok := result.Result.(string); ok && len(found) == 0 {
+				// Plain-text tool results still carry the job id
+				found = matchImageJobs(s)
+			}
+			for i := range found {
+				if found[i].Prompt == "" {
+					found[i].Prompt = prompts[result.ToolCallID]
+				}
+			}
+			jobs = append(jobs, found...)
+		}
+	}
+
+	if len(jobs) > 0 {
+		return jobs, nil
+	}
+
+	// Fall back to matching the decoded text
+	if jobs := matchImageJobs(text.String()); len(jobs) > 0 {
+		return jobs, nil
+	}
+
+	// Last resort: match the raw response body
+	if jobs := matchImageJobs(respText); len(jobs) > 0 {
+		return jobs, nil
+	}
+
+	return nil, fmt.Errorf("jobId not found in response")
+}
+
+// collectImageJobs walks a decoded tool result looking for jobId/prompt pairs
+func collectImageJobs(value interface{}) []imageJob {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		jobID := firstString(v, "jobId", "job_id", "jobID")
+		if jobID != "" {
+			return []imageJob{{JobID: jobID, Prompt: firstString(v, "prompt", "revisedPrompt", "revised_prompt")}}
+		}
+
+		var jobs []imageJob
+		for _, child := range v {
+			jobs = append(jobs, collectImageJobs(child)...)
+		}
+		return jobs
+
+	case []interface{}:
+		var jobs []imageJob
+		for _, child := range v {
+			jobs = append(jobs, collectImageJobs(child)...)
+		}
+		return jobs
+
+	case string:
+		// Tool results are sometimes JSON documents encoded as strings
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(v), &decoded); err == nil {
+			if _, isString := decoded.(string); !isString {
+				return collectImageJobs(decoded)
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchImageJobs finds jobId/prompt pairs in free-form text. The text is split
+// into one segment per job so every prompt is read from the segment of the job it
+// belongs to; prompts may appear either after or before their jobId.
+func matchImageJobs(text string) []imageJob {
+	locs := fallbackJobIDRegex.FindAllStringSubmatchIndex(text, -1)
+	if len(locs) == 0 {
+		return nil
+	}
+
+	// after[i] is the text between job i and the next job, before[i] the text
+	// between the previous job and job i
+	after := make([]string, len(locs))
+	before := make([]string, len(locs))
+	for i, loc := range locs {
+		end := len(text)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		start := 0
+		if i > 0 {
+			start = locs[i-1][1]
+		}
+		after[i] = text[loc[1]:end]
+		before[i] = text[start:loc[0]]
+	}
+
+	// Prompts precede their jobs only when one appears before the first job;
+	// otherwise each job's prompt follows its jobId
+	segments := after
+	if _, ok := findPrompt(before[0]); ok {
+		segments = before
+	}
+
+	jobs := make([]imageJob, 0, len(locs))
+	for i, loc := range locs {
+		prompt, _ := findPrompt(segments[i])
+		jobs = append(jobs, imageJob{JobID: text[loc[2]:loc[3]], Prompt: prompt})
+	}
+	return jobs
+}
+
+// findPrompt returns the first quoted prompt value in s
+func findPrompt(s string) (string, bool) {
+	loc := fallbackPromptRegex.FindStringIndex(s)
+	if loc == nil {
+		return "", false
+	}
+	return readQuoted(s[loc[1]-1:])
+}
+
+// readQuoted reads a string starting with a single or double quote up to the
+// matching unescaped closing quote, resolving backslash escapes on the way
+func readQuoted(s string) (string, bool) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '\'', '"', '\\':
+				b.WriteByte(s[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+		case c == quote:
+			return b.String(), true
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false
+}
+
+// firstString returns the first non-empty string value among keys
+func firstString(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := m[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
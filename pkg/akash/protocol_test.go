@@ -0,0 +1,81 @@
+package akash
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseImageJobs(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []imageJob
+	}{
+		{"single_quotes.txt", []imageJob{{JobID: "j1", Prompt: "a cat, sitting on a mat"}}},
+		{"double_quotes.txt", []imageJob{{JobID: "j2", Prompt: `a dog's "best" friend`}}},
+		{"escaped_quotes.txt", []imageJob{{JobID: "j3", Prompt: "it's a 'quoted' word, really"}}},
+		{"unicode.txt", []imageJob{{JobID: "j5", Prompt: "東京の夜景, café ☕ — 🌃"}}},
+		{"multiple_jobs.txt", []imageJob{{JobID: "j3", Prompt: "one"}, {JobID: "j4", Prompt: "two"}}},
+		{"prompt_first.txt", []imageJob{{JobID: "j6", Prompt: "sunset"}, {JobID: "j7", Prompt: "sunrise"}}},
+		{"stream.txt", []imageJob{{JobID: "j8", Prompt: "ocean 'waves'"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "jobs", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := parseImageJobs(string(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseImageJobs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseImageJobsToolParts(t *testing.T) {
+	resp := `9:{"toolCallId":"call_1","toolName":"generateImage","args":{"prompt":"a red fox"}}
+a:{"toolCallId":"call_1","result":{"jobId":"j9"}}`
+
+	got, err := parseImageJobs(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []imageJob{{JobID: "j9", Prompt: "a red fox"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseImageJobs() = %q, want %q", got, want)
+	}
+}
+
+func TestParseImageJobsMissing(t *testing.T) {
+	if _, err := parseImageJobs(`0:"no jobs here"`); err == nil {
+		t.Error("expected an error when no jobId is present")
+	}
+}
+
+func TestReadQuoted(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{`'plain' rest`, "plain", true},
+		{`"say \"hi\"" rest`, `say "hi"`, true},
+		{`'back\\slash'`, `back\slash`, true},
+		{`'line\nbreak'`, "line\nbreak", true},
+		{`'unterminated`, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := readQuoted(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("readQuoted(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package akash
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// sessionLifetime is how long a session is reused; Akash sessions last an hour
+const sessionLifetime = 55 * time.Minute
+
+// Session returns a valid session cookie, reusing the cached session until
+// shortly before it expires. Requests without an explicit session use it
+// automatically.
+func (c *Client) Session(ctx context.Context) (string, error) {
+	ctx, span := startSpan(ctx, "akash.Client.Session")
+	defer span.End()
+
+	c.mutex.RLock()
+	if c.session != "" && time.Now().Before(c.expiresAt) {
+		session := c.session
+		c.mutex.RUnlock()
+		span.SetAttributes(attribute.Bool("session.cached", true))
+		return session, nil
+	}
+	c.mutex.RUnlock()
+
+	// Need to get a new session
+	span.SetAttributes(attribute.Bool("session.cached", false))
+	session, err := c.refreshSession(ctx)
+	recordError(span, err)
+	return session, err
+}
+
+// refreshSession fetches a new session from Akash
+func (c *Client) refreshSession(ctx context.Context) (session string, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Double-check in case another goroutine already refreshed
+	if c.session != "" && time.Now().Before(c.expiresAt) {
+		return c.session, nil
+	}
+
+	if c.hooks.SessionRefresh != nil {
+		defer func() {
+			c.hooks.SessionRefresh(err)
+		}()
+	}
+
+	headers := map[string]string{
+		"Referer": c.baseURL + "/",
+		"Accept":  "*/*",
+	}
+
+	resp, err := c.get(ctx, EndpointSession, "/api/auth/session/", headers)
+	if err != nil {
+		return "", fmt.Errorf("failed to get session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("session request failed with status: %d", resp.StatusCode)
+	}
+
+	// Extract session token from Set-Cookie header
+	setCookieHeader := resp.Header.Get("Set-Cookie")
+	if setCookieHeader == "" {
+		return "", fmt.Errorf("no Set-Cookie header found")
+	}
+
+	session, err = extractSessionToken(setCookieHeader)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract session token: %w", err)
+	}
+
+	c.session = session
+	c.expiresAt = time.Now().Add(sessionLifetime)
+	return session, nil
+}
+
+// dropSession forgets the cached session if it is still session
+func (c *Client) dropSession(session string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.session == session {
+		c.session = ""
+	}
+}
+
+// sessionOr returns override when set and the client's session otherwise
+func (c *Client) sessionOr(ctx context.Context, override string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	return c.Session(ctx)
+}
+
+// extractSessionToken extracts the session cookie from a Set-Cookie header
+func extractSessionToken(setCookieHeader string) (string, error) {
+	// Example: session_token=0c647105a2175953f14b9f33c3e0100f405667b6c3e2507fb2cc6d0baff1e567; Path=/; ...
+	parts := strings.Split(setCookieHeader, ";")
+	if len(parts) == 0 {
+		return "", fmt.Errorf("invalid Set-Cookie header format")
+	}
+
+	sessionPart := strings.TrimSpace(parts[0])
+	if !strings.HasPrefix(sessionPart, "session_token=") {
+		return "", fmt.Errorf("session_token not found in Set-Cookie header")
+	}
+
+	token := strings.TrimPrefix(sessionPart, "session_token=")
+	if token == "" {
+		return "", fmt.Errorf("empty session token")
+	}
+
+	return fmt.Sprintf("session_token=%s", token), nil
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Client is a typed client for the akashchat-api-go proxy
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *HTTPClient
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used for requests. Streams are bound by
+// the request context, so avoid a client Timeout when streaming long replies.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = WrapHTTPClient(httpClient).WithTracePropagation()
+	}
+}
+
+// WithAPIKey sends key as a bearer token, for proxies started with API_KEYS or
+// deployed behind a gateway
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// NewClient creates a new Client for the proxy at baseURL, such as http://localhost:16571
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    WrapHTTPClient(&http.Client{}).WithTracePropagation(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ChatCompletion generates a complete chat reply
+func (c *Client) ChatCompletion(ctx context.Context, req ChatCompletionRequest) (*ChatCompletion, error) {
+	req.Stream = false
+
+	var completion ChatCompletion
+	if err := c.doJSON(ctx, "POST", "/v1/chat/completions", req, &completion); err != nil {
+		return nil, err
+	}
+	return &completion, nil
+}
+
+// ListModels lists every model and alias with its metadata, including models
+// that are currently unavailable
+func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
+	var list struct {
+		Data []Model `json:"data"`
+	}
+	if err := c.doJSON(ctx, "GET", "/v1/models?extended=true", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Data, nil
+}
+
+// GenerateImage generates images with AkashGen
+func (c *Client) GenerateImage(ctx context.Context, req ImageRequest) (*ImageResponse, error) {
+	var resp ImageResponse
+	if err := c.doJSON(ctx, "POST", "/v1/images/generations", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// doJSON sends body as JSON and decodes a successful response into out
+func (c *Client) doJSON(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send performs a request and converts error responses to an APIError. The
+// caller closes the body of a successful response.
+func (c *Client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	headers := map[string]string{"Accept": "application/json"}
+	if c.apiKey != "" {
+		headers["Authorization"] = "Bearer " + c.apiKey
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+		headers["Content-Type"] = "application/json"
+	}
+
+	resp, err := c.http.do(ctx, method, c.baseURL+path, reader, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return nil, parseError(resp.StatusCode, data)
+	}
+	return resp, nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrModelNotFound is matched by errors for unknown or unavailable models
+	ErrModelNotFound = errors.New("model not found")
+	// ErrContextLengthExceeded is matched by errors for prompts that do not fit the model
+	ErrContextLengthExceeded = errors.New("context length exceeded")
+	// ErrStreamInterrupted is returned when a stream ends before its last chunk
+	ErrStreamInterrupted = errors.New("stream ended before the reply was complete")
+)
+
+// APIError is an error response from the proxy. Use errors.Is with
+// ErrModelNotFound or ErrContextLengthExceeded to check for common failures.
+type APIError struct {
+	StatusCode  int
+	Type        string
+	Message     string
+	Suggestions []string
+}
+
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("akashchat: %d %s: %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("akashchat: %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error matching the error type, if any
+func (e *APIError) Unwrap() error {
+	switch e.Type {
+	case "model_not_found":
+		return ErrModelNotFound
+	case "context_length_exceeded":
+		return ErrContextLengthExceeded
+	}
+	return nil
+}
+
+// errorBody is the error format used by the proxy
+type errorBody struct {
+	Code int `json:"code"`
+	Data struct {
+		Message     string   `json:"msg"`
+		Type        string   `json:"type"`
+		Suggestions []string `json:"suggestions"`
+	} `json:"data"`
+	// Error is set by the few endpoints that return {"error": "..."}
+	Error string `json:"error"`
+}
+
+// parseError builds an APIError from an error response body
+func parseError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var eb errorBody
+	if err := json.Unmarshal(body, &eb); err != nil {
+		apiErr.Message = http.StatusText(statusCode)
+		return apiErr
+	}
+
+	apiErr.Type = eb.Data.Type
+	apiErr.Message = eb.Data.Message
+	apiErr.Suggestions = eb.Data.Suggestions
+	if apiErr.Message == "" {
+		apiErr.Message = eb.Error
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+	return apiErr
+}
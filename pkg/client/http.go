@@ -1,14 +1,23 @@
 package client
 
 import (
+	"context"
 	"io"
 	"net/http"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // HTTPClient wraps http.Client with additional functionality
 type HTTPClient struct {
-	client *http.Client
+	client    *http.Client
+	propagate bool
 }
 
 // NewHTTPClient creates a new HTTPClient instance
@@ -20,10 +29,55 @@ func NewHTTPClient() *HTTPClient {
 	}
 }
 
+// WrapHTTPClient creates an HTTPClient that sends requests with httpClient
+func WrapHTTPClient(httpClient *http.Client) *HTTPClient {
+	return &HTTPClient{client: httpClient}
+}
+
+// WithTracePropagation makes c send the W3C trace context with each request.
+// Enable it only for servers that belong to the same deployment, since the
+// headers reveal internal trace IDs.
+func (c *HTTPClient) WithTracePropagation() *HTTPClient {
+	c.propagate = true
+	return c
+}
+
 // Get performs a GET request with optional headers
 func (c *HTTPClient) Get(url string, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	return c.GetContext(context.Background(), url, headers)
+}
+
+// Post performs a POST request with body and optional headers
+func (c *HTTPClient) Post(url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	return c.PostContext(context.Background(), url, body, headers)
+}
+
+// GetContext performs a GET request bound to ctx with optional headers
+func (c *HTTPClient) GetContext(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	return c.do(ctx, "GET", url, nil, headers)
+}
+
+// PostContext performs a POST request bound to ctx with body and optional headers
+func (c *HTTPClient) PostContext(ctx context.Context, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	return c.do(ctx, "POST", url, body, headers)
+}
+
+// do sends a request inside a client span that ends once the response body is
+// closed, so streamed responses are timed in full
+func (c *HTTPClient) do(ctx context.Context, method, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	ctx, span := otel.Tracer("github.com/006lp/akashchat-api-go/pkg/client").Start(ctx, "HTTP "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("url.full", url),
+		),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
 		return nil, err
 	}
 
@@ -31,21 +85,49 @@ func (c *HTTPClient) Get(url string, headers map[string]string) (*http.Response,
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
+	if c.propagate {
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
 
-	return c.client.Do(req)
-}
-
-// Post performs a POST request with body and optional headers
-func (c *HTTPClient) Post(url string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest("POST", url, body)
+	resp, err := c.client.Do(req)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
 		return nil, err
 	}
 
-	// Add headers
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		span.SetStatus(codes.Error, resp.Status)
 	}
 
-	return c.client.Do(req)
-}
\ No newline at end of file
+	resp.Body = &spanBody{ReadCloser: resp.Body, span: span}
+	return resp, nil
+}
+
+// spanBody ends the span of a request when its response body is closed
+type spanBody struct {
+	io.ReadCloser
+	span trace.Span
+	once sync.Once
+}
+
+// Read reads from the body, recording errors other than EOF on the span
+func (b *spanBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		b.span.RecordError(err)
+		b.span.SetStatus(codes.Error, err.Error())
+	}
+	return n, err
+}
+
+// Close closes the body and ends the span
+func (b *spanBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.span.End()
+	})
+	return err
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// useTestTracer installs a tracer provider that records spans for the duration of the test
+func useTestTracer(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+	return recorder
+}
+
+func TestHTTPClientTracePropagation(t *testing.T) {
+	useTestTracer(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	tests := []struct {
+		name   string
+		client *HTTPClient
+		want   bool
+	}{
+		{"disabled by default", WrapHTTPClient(server.Client()), false},
+		{"enabled", WrapHTTPClient(server.Client()).WithTracePropagation(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceparent = ""
+			resp, err := tt.client.GetContext(ctx, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if (traceparent != "") != tt.want {
+				t.Errorf("traceparent = %q, want sent: %v", traceparent, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPClientSpanEndsWithBody(t *testing.T) {
+	recorder := useTestTracer(t)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "first")
+		w.(http.Flusher).Flush()
+		<-release
+		io.WriteString(w, " second")
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := WrapHTTPClient(server.Client()).GetContext(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The span stays open while the body is still being streamed
+	if n := len(recorder.Ended()); n != 0 {
+		t.Fatalf("%d spans ended before the body was read", n)
+	}
+	close(release)
+	data, _ := io.ReadAll(resp.Body)
+	if string(data) != "first second" {
+		t.Errorf("body = %q", data)
+	}
+	resp.Body.Close()
+	resp.Body.Close()
+
+	if n := len(recorder.Ended()); n != 1 {
+		t.Errorf("%d spans ended after the body was closed, want 1", n)
+	}
+}
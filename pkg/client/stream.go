@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// ChatCompletionStream streams a chat reply. The request is sent when iteration
+// starts; an error ends the sequence and is yielded with a nil chunk. A stream
+// that breaks off before the final chunk yields ErrStreamInterrupted.
+//
+//	for chunk, err := range c.ChatCompletionStream(ctx, req) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Print(chunk.Content())
+//	}
+func (c *Client) ChatCompletionStream(ctx context.Context, req ChatCompletionRequest) iter.Seq2[*ChatCompletionChunk, error] {
+	return func(yield func(*ChatCompletionChunk, error) bool) {
+		req.Stream = true
+
+		resp, err := c.send(ctx, "POST", "/v1/chat/completions", req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		finished := false
+		events := newSSEReader(resp.Body)
+		for {
+			event, err := events.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if event.data == "[DONE]" {
+				return
+			}
+
+			chunk, err := parseChunk(event.data)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, choice := range chunk.Choices {
+				if choice.FinishReason != "" {
+					finished = true
+				}
+			}
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+
+		if !finished {
+			yield(nil, ErrStreamInterrupted)
+		}
+	}
+}
+
+// parseChunk decodes a stream chunk, converting an in-stream error payload to an APIError
+func parseChunk(data string) (*ChatCompletionChunk, error) {
+	var payload struct {
+		ChatCompletionChunk
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return nil, fmt.Errorf("akashchat: invalid stream chunk: %w", err)
+	}
+	if payload.Code != 0 {
+		return nil, parseError(payload.Code, []byte(data))
+	}
+	return &payload.ChatCompletionChunk, nil
+}
+
+// sseEvent is a server-sent event
+type sseEvent struct {
+	event string
+	data  string
+}
+
+// sseReader parses a text/event-stream body
+type sseReader struct {
+	reader *bufio.Reader
+}
+
+// newSSEReader creates a new sseReader reading from r
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{reader: bufio.NewReader(r)}
+}
+
+// next returns the next event that carries data. Multi-line data is joined with
+// newlines, comments and unknown fields are skipped, and an event cut off by the
+// end of the body is discarded, as the SSE specification requires.
+func (s *sseReader) next() (sseEvent, error) {
+	var event sseEvent
+	var data []string
+	hasData := false
+
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return sseEvent{}, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if err == io.EOF {
+				return sseEvent{}, io.EOF
+			}
+			if hasData {
+				event.data = strings.Join(data, "\n")
+				return event, nil
+			}
+			event = sseEvent{}
+			continue
+		}
+		if err == io.EOF {
+			return sseEvent{}, io.EOF
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.event = value
+		case "data":
+			data = append(data, value)
+			hasData = true
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newStreamServer serves body as the event stream of /v1/chat/completions
+func newStreamServer(t *testing.T, status int, body string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if data, _ := io.ReadAll(r.Body); !strings.Contains(string(data), `"stream":true`) {
+			t.Errorf("request body %s does not ask for a stream", data)
+		}
+		if status == http.StatusOK {
+			w.Header().Set("Content-Type", "text/event-stream")
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL)
+}
+
+// collect drains a stream, returning the streamed text and the error that ended it
+func collect(c *Client) (string, int, error) {
+	var text strings.Builder
+	chunks := 0
+	for chunk, err := range c.ChatCompletionStream(context.Background(), ChatCompletionRequest{Model: "m"}) {
+		if err != nil {
+			return text.String(), chunks, err
+		}
+		text.WriteString(chunk.Content())
+		chunks++
+	}
+	return text.String(), chunks, nil
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	c := newStreamServer(t, http.StatusOK, ": keep-alive\n\n"+
+		"data: {\"choices\":[{\"delta\":{\"role\":\"assistant\",\"content\":\"Hello\"}}]}\n\n"+
+		"event: chunk\r\ndata: {\"choices\":[{\"delta\":{\"content\":\" world\"}}]}\r\n\r\n"+
+		"data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}],\"usage\":{\"total_tokens\":3}}\n\n")
+
+	text, chunks, err := collect(c)
+	if err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+	if text != "Hello world" || chunks != 3 {
+		t.Errorf("text = %q from %d chunks, want Hello world from 3", text, chunks)
+	}
+}
+
+func TestChatCompletionStreamDone(t *testing.T) {
+	// A [DONE] marker ends the stream even without a finish reason
+	c := newStreamServer(t, http.StatusOK,
+		"data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n")
+
+	if text, _, err := collect(c); err != nil || text != "Hi" {
+		t.Errorf("text = %q, error = %v, want Hi without an error", text, err)
+	}
+}
+
+func TestChatCompletionStreamMultilineData(t *testing.T) {
+	c := newStreamServer(t, http.StatusOK,
+		"data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"},\ndata: \"finish_reason\":\"stop\"}]}\n\n")
+
+	if text, _, err := collect(c); err != nil || text != "Hi" {
+		t.Errorf("text = %q, error = %v, want data lines joined into one chunk", text, err)
+	}
+}
+
+func TestChatCompletionStreamInterrupted(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		text string
+	}{
+		{"no final chunk", "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n", "Hel"},
+		// The trailing event is incomplete and must not be delivered
+		{"cut off event", "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\ndata: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}", "Hel"},
+		{"empty body", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, _, err := collect(newStreamServer(t, http.StatusOK, tt.body))
+			if !errors.Is(err, ErrStreamInterrupted) {
+				t.Errorf("error = %v, want ErrStreamInterrupted", err)
+			}
+			if text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+		})
+	}
+}
+
+func TestChatCompletionStreamErrors(t *testing.T) {
+	// An error response before the stream starts
+	c := newStreamServer(t, http.StatusNotFound,
+		`{"code":404,"data":{"msg":"model not found","type":"model_not_found","suggestions":["m-1"]}}`)
+	_, _, err := collect(c)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrModelNotFound) {
+		t.Fatalf("error = %v, want an APIError matching ErrModelNotFound", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || len(apiErr.Suggestions) != 1 {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+
+	// An error payload sent once the stream has started
+	c = newStreamServer(t, http.StatusOK, "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n"+
+		"data: {\"code\":500,\"data\":{\"msg\":\"upstream failed\"}}\n\n")
+	text, _, err := collect(c)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 || apiErr.Message != "upstream failed" {
+		t.Errorf("error = %v, want the in-stream APIError", err)
+	}
+	if text != "Hel" {
+		t.Errorf("text = %q, want the chunks before the error", text)
+	}
+
+	// A chunk that is not JSON
+	c = newStreamServer(t, http.StatusOK, "data: not json\n\n")
+	if _, _, err := collect(c); err == nil || errors.Is(err, ErrStreamInterrupted) {
+		t.Errorf("error = %v, want a decoding error", err)
+	}
+}
+
+func TestChatCompletionStreamStopsWhenCallerBreaks(t *testing.T) {
+	c := newStreamServer(t, http.StatusOK, "data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\n"+
+		"data: {\"choices\":[{\"delta\":{\"content\":\"b\"}}]}\n\n")
+
+	seen := 0
+	for _, err := range c.ChatCompletionStream(context.Background(), ChatCompletionRequest{Model: "m"}) {
+		if err != nil {
+			t.Fatalf("error after break = %v", err)
+		}
+		seen++
+		break
+	}
+	if seen != 1 {
+		t.Errorf("saw %d chunks, want 1", seen)
+	}
+}
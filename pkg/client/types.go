@@ -0,0 +1,125 @@
+package client
+
+// Message is a single chat message
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatCompletionRequest is a /v1/chat/completions request. Leave the optional
+// fields nil to use the server defaults.
+type ChatCompletionRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	TopP        *float64  `json:"topP,omitempty"`
+	MaxTokens   *int      `json:"max_tokens,omitempty"`
+	Stop        []string  `json:"stop,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+// ChatCompletion is a complete chat reply
+type ChatCompletion struct {
+	ID      string   `json:"id"`
+	Object  string   `json:"object"`
+	Created int64    `json:"created"`
+	Model   string   `json:"model"`
+	Choices []Choice `json:"choices"`
+	Usage   Usage    `json:"usage"`
+}
+
+// Content returns the text of the first choice
+func (c *ChatCompletion) Content() string {
+	if len(c.Choices) == 0 {
+		return ""
+	}
+	return c.Choices[0].Message.Content
+}
+
+// Choice is a single choice of a chat reply
+type Choice struct {
+	Index        int     `json:"index"`
+	Message      Message `json:"message"`
+	FinishReason string  `json:"finish_reason"`
+}
+
+// Usage holds the token counts of a generation
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ChatCompletionChunk is a piece of a streamed chat reply. The last chunk carries
+// the finish reason and usage.
+type ChatCompletionChunk struct {
+	ID      string        `json:"id"`
+	Object  string        `json:"object"`
+	Created int64         `json:"created"`
+	Model   string        `json:"model"`
+	Choices []ChunkChoice `json:"choices"`
+	Usage   *Usage        `json:"usage,omitempty"`
+}
+
+// Content returns the text delta of the first choice
+func (c *ChatCompletionChunk) Content() string {
+	if len(c.Choices) == 0 {
+		return ""
+	}
+	return c.Choices[0].Delta.Content
+}
+
+// ChunkChoice is a single choice of a stream chunk
+type ChunkChoice struct {
+	Index        int    `json:"index"`
+	Delta        Delta  `json:"delta"`
+	FinishReason string `json:"finish_reason,omitempty"`
+}
+
+// Delta is the change a stream chunk adds to the reply
+type Delta struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// Model describes a model or alias served by the proxy
+type Model struct {
+	ID           string   `json:"id"`
+	Created      int64    `json:"created"`
+	OwnedBy      string   `json:"owned_by"`
+	Root         string   `json:"root"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	TokenLimit   int      `json:"token_limit,omitempty"`
+	Parameters   string   `json:"parameters,omitempty"`
+	Architecture string   `json:"architecture,omitempty"`
+	Available    bool     `json:"available"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// ImageRequest is a /v1/images/generations request
+type ImageRequest struct {
+	Prompt         string `json:"prompt"`
+	Model          string `json:"model,omitempty"`
+	Size           string `json:"size,omitempty"`
+	AspectRatio    string `json:"aspect_ratio,omitempty"`
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	Seed           *int64 `json:"seed,omitempty"`
+	Style          string `json:"style,omitempty"`
+	N              int    `json:"n,omitempty"`
+	Verbatim       bool   `json:"verbatim_prompt,omitempty"`
+}
+
+// ImageResponse holds the generated images
+type ImageResponse struct {
+	Created int64   `json:"created"`
+	Data    []Image `json:"data"`
+}
+
+// Image is a single generated image
+type Image struct {
+	URL            string `json:"url"`
+	RevisedPrompt  string `json:"revised_prompt"`
+	OriginalPrompt string `json:"original_prompt"`
+	JobID          string `json:"job_id"`
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: akashchat/v1/akashchat.proto
+
+package akashchatv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AkashChat_ListModels_FullMethodName           = "/akashchat.v1.AkashChat/ListModels"
+	AkashChat_ChatCompletion_FullMethodName       = "/akashchat.v1.AkashChat/ChatCompletion"
+	AkashChat_StreamChatCompletion_FullMethodName = "/akashchat.v1.AkashChat/StreamChatCompletion"
+	AkashChat_GenerateImage_FullMethodName        = "/akashchat.v1.AkashChat/GenerateImage"
+)
+
+// AkashChatClient is the client API for AkashChat service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AkashChat exposes Akash Chat models over gRPC
+type AkashChatClient interface {
+	// ListModels lists the catalog models and configured aliases
+	ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error)
+	// ChatCompletion generates a complete chat reply
+	ChatCompletion(ctx context.Context, in *ChatCompletionRequest, opts ...grpc.CallOption) (*ChatCompletionResponse, error)
+	// StreamChatCompletion streams a chat reply as it is generated
+	StreamChatCompletion(ctx context.Context, in *ChatCompletionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatCompletionChunk], error)
+	// GenerateImage generates images with AkashGen
+	GenerateImage(ctx context.Context, in *GenerateImageRequest, opts ...grpc.CallOption) (*GenerateImageResponse, error)
+}
+
+type akashChatClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAkashChatClient(cc grpc.ClientConnInterface) AkashChatClient {
+	return &akashChatClient{cc}
+}
+
+func (c *akashChatClient) ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListModelsResponse)
+	err := c.cc.Invoke(ctx, AkashChat_ListModels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *akashChatClient) ChatCompletion(ctx context.Context, in *ChatCompletionRequest, opts ...grpc.CallOption) (*ChatCompletionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChatCompletionResponse)
+	err := c.cc.Invoke(ctx, AkashChat_ChatCompletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *akashChatClient) StreamChatCompletion(ctx context.Context, in *ChatCompletionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatCompletionChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AkashChat_ServiceDesc.Streams[0], AkashChat_StreamChatCompletion_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChatCompletionRequest, ChatCompletionChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AkashChat_StreamChatCompletionClient = grpc.ServerStreamingClient[ChatCompletionChunk]
+
+func (c *akashChatClient) GenerateImage(ctx context.Context, in *GenerateImageRequest, opts ...grpc.CallOption) (*GenerateImageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateImageResponse)
+	err := c.cc.Invoke(ctx, AkashChat_GenerateImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AkashChatServer is the server API for AkashChat service.
+// All implementations must embed UnimplementedAkashChatServer
+// for forward compatibility.
+//
+// AkashChat exposes Akash Chat models over gRPC
+type AkashChatServer interface {
+	// ListModels lists the catalog models and configured aliases
+	ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error)
+	// ChatCompletion generates a complete chat reply
+	ChatCompletion(context.Context, *ChatCompletionRequest) (*ChatCompletionResponse, error)
+	// StreamChatCompletion streams a chat reply as it is generated
+	StreamChatCompletion(*ChatCompletionRequest, grpc.ServerStreamingServer[ChatCompletionChunk]) error
+	// GenerateImage generates images with AkashGen
+	GenerateImage(context.Context, *GenerateImageRequest) (*GenerateImageResponse, error)
+	mustEmbedUnimplementedAkashChatServer()
+}
+
+// UnimplementedAkashChatServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAkashChatServer struct{}
+
+func (UnimplementedAkashChatServer) ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModels not implemented")
+}
+func (UnimplementedAkashChatServer) ChatCompletion(context.Context, *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChatCompletion not implemented")
+}
+func (UnimplementedAkashChatServer) StreamChatCompletion(*ChatCompletionRequest, grpc.ServerStreamingServer[ChatCompletionChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamChatCompletion not implemented")
+}
+func (UnimplementedAkashChatServer) GenerateImage(context.Context, *GenerateImageRequest) (*GenerateImageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateImage not implemented")
+}
+func (UnimplementedAkashChatServer) mustEmbedUnimplementedAkashChatServer() {}
+func (UnimplementedAkashChatServer) testEmbeddedByValue()                   {}
+
+// UnsafeAkashChatServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AkashChatServer will
+// result in compilation errors.
+type UnsafeAkashChatServer interface {
+	mustEmbedUnimplementedAkashChatServer()
+}
+
+func RegisterAkashChatServer(s grpc.ServiceRegistrar, srv AkashChatServer) {
+	// If the following call pancis, it indicates UnimplementedAkashChatServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AkashChat_ServiceDesc, srv)
+}
+
+func _AkashChat_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AkashChatServer).ListModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AkashChat_ListModels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AkashChatServer).ListModels(ctx, req.(*ListModelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AkashChat_ChatCompletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChatCompletionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AkashChatServer).ChatCompletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AkashChat_ChatCompletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AkashChatServer).ChatCompletion(ctx, req.(*ChatCompletionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AkashChat_StreamChatCompletion_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChatCompletionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AkashChatServer).StreamChatCompletion(m, &grpc.GenericServerStream[ChatCompletionRequest, ChatCompletionChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AkashChat_StreamChatCompletionServer = grpc.ServerStreamingServer[ChatCompletionChunk]
+
+func _AkashChat_GenerateImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AkashChatServer).GenerateImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AkashChat_GenerateImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AkashChatServer).GenerateImage(ctx, req.(*GenerateImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AkashChat_ServiceDesc is the grpc.ServiceDesc for AkashChat service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AkashChat_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "akashchat.v1.AkashChat",
+	HandlerType: (*AkashChatServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListModels",
+			Handler:    _AkashChat_ListModels_Handler,
+		},
+		{
+			MethodName: "ChatCompletion",
+			Handler:    _AkashChat_ChatCompletion_Handler,
+		},
+		{
+			MethodName: "GenerateImage",
+			Handler:    _AkashChat_GenerateImage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamChatCompletion",
+			Handler:       _AkashChat_StreamChatCompletion_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "akashchat/v1/akashchat.proto",
+}